@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -72,22 +72,26 @@ type Account struct {
 
 // Sync updates account information
 func (account *Account) Sync() error {
+	return account.SyncContext(context.Background())
+}
+
+// SyncContext is the context-aware version of Sync.
+func (account *Account) SyncContext(ctx context.Context) error {
 	insta := account.inst
 	data, err := insta.prepareData()
 	if err != nil {
 		return err
 	}
-	body, err := insta.sendRequest(&reqOptions{
+	body, err := insta.sendRequest(ctx, &reqOptions{
 		Endpoint: urlCurrentUser,
-		Query:    generateSignature(data),
+		Query:    insta.generateSignature(data),
 		IsPost:   false,
 	})
 	if err == nil {
 		resp := profResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
-			*account = resp.Account
-			account.inst = insta
+			insta.updateAccount(account, &resp.Account)
 		}
 	}
 	return err
@@ -100,6 +104,11 @@ func (account *Account) Sync() error {
 //
 // See example: examples/account/changePass.go
 func (account *Account) ChangePassword(old, new string) error {
+	return account.ChangePasswordContext(context.Background(), old, new)
+}
+
+// ChangePasswordContext is the context-aware version of ChangePassword.
+func (account *Account) ChangePasswordContext(ctx context.Context, old, new string) error {
 	insta := account.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -109,10 +118,10 @@ func (account *Account) ChangePassword(old, new string) error {
 		},
 	)
 	if err == nil {
-		_, err = insta.sendRequest(
+		_, err = insta.sendRequest(ctx,
 			&reqOptions{
 				Endpoint: urlChangePass,
-				Query:    generateSignature(data),
+				Query:    insta.generateSignature(data),
 				IsPost:   true,
 			},
 		)
@@ -131,25 +140,29 @@ type profResp struct {
 //
 // See example: examples/account/removeProfilePic.go
 func (account *Account) RemoveProfilePic() error {
+	return account.RemoveProfilePicContext(context.Background())
+}
+
+// RemoveProfilePicContext is the context-aware version of RemoveProfilePic.
+func (account *Account) RemoveProfilePicContext(ctx context.Context) error {
 	insta := account.inst
 	data, err := insta.prepareData()
 	if err != nil {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlRemoveProfPic,
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
 	if err == nil {
 		resp := profResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
-			*account = resp.Account
-			account.inst = insta
+			insta.updateAccount(account, &resp.Account)
 		}
 	}
 	return err
@@ -161,25 +174,29 @@ func (account *Account) RemoveProfilePic() error {
 //
 // See example: examples/account/setPrivate.go
 func (account *Account) SetPrivate() error {
+	return account.SetPrivateContext(context.Background())
+}
+
+// SetPrivateContext is the context-aware version of SetPrivate.
+func (account *Account) SetPrivateContext(ctx context.Context) error {
 	insta := account.inst
 	data, err := insta.prepareData()
 	if err != nil {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSetPrivate,
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
 	if err == nil {
 		resp := profResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
-			*account = resp.Account
-			account.inst = insta
+			insta.updateAccount(account, &resp.Account)
 		}
 	}
 	return err
@@ -191,25 +208,29 @@ func (account *Account) SetPrivate() error {
 //
 // See example: examples/account/setPublic.go
 func (account *Account) SetPublic() error {
+	return account.SetPublicContext(context.Background())
+}
+
+// SetPublicContext is the context-aware version of SetPublic.
+func (account *Account) SetPublicContext(ctx context.Context) error {
 	insta := account.inst
 	data, err := insta.prepareData()
 	if err != nil {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSetPublic,
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
 	if err == nil {
 		resp := profResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
-			*account = resp.Account
-			account.inst = insta
+			insta.updateAccount(account, &resp.Account)
 		}
 	}
 	return err
@@ -284,13 +305,18 @@ func (account *Account) Stories() *StoryMedia {
 //
 // For pagination use FeedMedia.Next()
 func (account *Account) Tags(minTimestamp []byte) (*FeedMedia, error) {
+	return account.TagsContext(context.Background(), minTimestamp)
+}
+
+// TagsContext is the context-aware version of Tags.
+func (account *Account) TagsContext(ctx context.Context, minTimestamp []byte) (*FeedMedia, error) {
 	timestamp := b2s(minTimestamp)
-	body, err := account.inst.sendRequest(
+	body, err := account.inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserTags, account.ID),
 			Query: map[string]string{
 				"max_id":         "",
-				"rank_token":     account.inst.rankToken,
+				"rank_token":     account.inst.rankToken.Get(),
 				"min_timestamp":  timestamp,
 				"ranked_content": "true",
 			},
@@ -301,7 +327,7 @@ func (account *Account) Tags(minTimestamp []byte) (*FeedMedia, error) {
 	}
 
 	media := &FeedMedia{}
-	err = json.Unmarshal(body, media)
+	err = account.inst.unmarshal(body, media)
 	media.inst = account.inst
 	media.endpoint = urlUserTags
 	media.uid = account.ID
@@ -310,10 +336,15 @@ func (account *Account) Tags(minTimestamp []byte) (*FeedMedia, error) {
 
 // Saved returns saved media.
 func (account *Account) Saved() (*SavedMedia, error) {
-	body, err := account.inst.sendSimpleRequest(urlUserTags, account.ID)
+	return account.SavedContext(context.Background())
+}
+
+// SavedContext is the context-aware version of Saved.
+func (account *Account) SavedContext(ctx context.Context) (*SavedMedia, error) {
+	body, err := account.inst.sendSimpleRequest(ctx, urlUserTags, account.ID)
 	if err == nil {
 		media := &SavedMedia{}
-		err = json.Unmarshal(body, &media)
+		err = account.inst.unmarshal(body, &media)
 		return media, err
 	}
 	return nil, err
@@ -324,10 +355,10 @@ type editResp struct {
 	Account Account `json:"user"`
 }
 
-func (account *Account) edit() {
+func (account *Account) edit(ctx context.Context) {
 	insta := account.inst
 	acResp := editResp{}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlCurrentUser,
 			Query: map[string]string{
@@ -336,10 +367,9 @@ func (account *Account) edit() {
 		},
 	)
 	if err == nil {
-		err = json.Unmarshal(body, &acResp)
+		err = insta.unmarshal(body, &acResp)
 		if err == nil {
-			acResp.Account.inst = insta
-			*account = acResp.Account
+			insta.updateAccount(account, &acResp.Account)
 		}
 	}
 }
@@ -348,7 +378,12 @@ func (account *Account) edit() {
 //
 // This function updates current Account information.
 func (account *Account) SetBiography(bio string) error {
-	account.edit() // preparing to edit
+	return account.SetBiographyContext(context.Background(), bio)
+}
+
+// SetBiographyContext is the context-aware version of SetBiography.
+func (account *Account) SetBiographyContext(ctx context.Context, bio string) error {
+	account.edit(ctx) // preparing to edit
 	insta := account.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -359,10 +394,10 @@ func (account *Account) SetBiography(bio string) error {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSetBiography,
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -374,9 +409,11 @@ func (account *Account) SetBiography(bio string) error {
 			} `json:"user"`
 			Status string `json:"status"`
 		}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
+			insta.mu.Lock()
 			account.Biography = resp.User.Biography
+			insta.mu.Unlock()
 		}
 	}
 	return err
@@ -394,8 +431,13 @@ func (account *Account) Liked() *FeedMedia {
 
 // PendingFollowRequests returns pending follow requests.
 func (account *Account) PendingFollowRequests() ([]User, error) {
+	return account.PendingFollowRequestsContext(context.Background())
+}
+
+// PendingFollowRequestsContext is the context-aware version of PendingFollowRequests.
+func (account *Account) PendingFollowRequestsContext(ctx context.Context) ([]User, error) {
 	insta := account.inst
-	resp, err := insta.sendRequest(
+	resp, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlFriendshipPending,
 		},
@@ -410,7 +452,7 @@ func (account *Account) PendingFollowRequests() ([]User, error) {
 		// TODO: SuggestedUsers
 		Status string `json:"status"`
 	}
-	err = json.Unmarshal(resp, &result)
+	err = insta.unmarshal(resp, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"strconv"
 )
 
@@ -59,11 +59,16 @@ func (act *FollowingActivity) Error() error {
 //
 // See example:
 func (act *FollowingActivity) Next() bool {
+	return act.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (act *FollowingActivity) NextContext(ctx context.Context) bool {
 	if act.err != nil {
 		return false
 	}
 	insta := act.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlActivityFollowing,
 			Query: map[string]string{
@@ -74,7 +79,7 @@ func (act *FollowingActivity) Next() bool {
 	)
 	if err == nil {
 		act2 := FollowingActivity{}
-		err = json.Unmarshal(body, &act2)
+		err = insta.unmarshal(body, &act2)
 		if err == nil {
 			*act = act2
 			act.inst = insta
@@ -160,11 +165,16 @@ func (act *MineActivity) Error() error {
 //
 // See example: examples/activity/recent.go
 func (act *MineActivity) Next() bool {
+	return act.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (act *MineActivity) NextContext(ctx context.Context) bool {
 	if act.err != nil {
 		return false
 	}
 	insta := act.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlActivityRecent,
 			Query: map[string]string{
@@ -175,7 +185,7 @@ func (act *MineActivity) Next() bool {
 	)
 	if err == nil {
 		act2 := MineActivity{}
-		err = json.Unmarshal(body, &act2)
+		err = insta.unmarshal(body, &act2)
 		if err == nil {
 			*act = act2
 			act.inst = insta
@@ -0,0 +1,85 @@
+package goinsta
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AppProfile describes the instagram app presented by a session:
+// its version, signing key and experiments.
+//
+// A session keeps the profile it logged in with, so the profile is stored
+// by Export and restored by Import.
+type AppProfile struct {
+	// Version is the app version, like "107.0.0.27.121".
+	Version string `json:"version"`
+	// AppID is sent in the X-IG-App-ID header.
+	AppID string `json:"app_id"`
+	// Capabilities is sent in the X-IG-Capabilities header.
+	Capabilities string `json:"capabilities"`
+	// SigKey is the key signing request bodies.
+	SigKey        string `json:"sig_key"`
+	SigKeyVersion string `json:"sig_key_version"`
+	// Experiments is the comma separated list of experiments sent at login.
+	Experiments string `json:"experiments"`
+}
+
+var appProfiles = map[string]AppProfile{
+	goInstaAppVersion: {
+		Version:       goInstaAppVersion,
+		AppID:         fbAnalytics,
+		Capabilities:  igCapabilities,
+		SigKey:        goInstaIGSigKey,
+		SigKeyVersion: goInstaSigKeyVersion,
+		Experiments:   goInstaExperiments,
+	},
+}
+
+// DefaultAppProfile returns the app profile used by sessions without a profile.
+func DefaultAppProfile() AppProfile {
+	return appProfiles[goInstaAppVersion]
+}
+
+// AppProfileFor returns the bundled app profile of version.
+func AppProfileFor(version string) (AppProfile, error) {
+	p, ok := appProfiles[version]
+	if !ok {
+		return p, fmt.Errorf("no app profile for version %s", version)
+	}
+	return p, nil
+}
+
+// AppVersions returns the versions of the bundled app profiles.
+func AppVersions() []string {
+	versions := make([]string, 0, len(appProfiles))
+	for v := range appProfiles {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// AppProfile returns the app profile presented by the session.
+func (inst *Instagram) AppProfile() AppProfile {
+	return inst.app
+}
+
+// WithAppProfile sets the app profile presented by the session.
+func WithAppProfile(p AppProfile) Option {
+	return func(inst *Instagram) error {
+		inst.app = p
+		return nil
+	}
+}
+
+// WithAppVersion selects the bundled app profile of version.
+// See AppVersions.
+func WithAppVersion(version string) Option {
+	return func(inst *Instagram) error {
+		p, err := AppProfileFor(version)
+		if err == nil {
+			inst.app = p
+		}
+		return err
+	}
+}
@@ -0,0 +1,99 @@
+package goinsta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAppProfile(t *testing.T) {
+	if _, err := NewWithOptions("user", "pass", WithAppVersion("1.0.0")); err == nil {
+		t.Fatal("unknown app version accepted")
+	}
+
+	p := DefaultAppProfile()
+	p.Version = "999.0.0.0.1"
+	p.SigKey = "key"
+	p.SigKeyVersion = "5"
+	insta, err := NewWithOptions("user", "pass", WithAppProfile(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := insta.generateSignature("{}")
+	if sig["ig_sig_key_version"] != "5" || sig["signed_body"] != generateHMAC("{}", "key")+".{}" {
+		t.Fatalf("body not signed with the session profile: %v", sig)
+	}
+	if !strings.HasPrefix(insta.userAgent(), "Instagram 999.0.0.0.1 ") {
+		t.Fatalf("got user agent %q", insta.userAgent())
+	}
+	if app := insta.config().App; app == nil || *app != p {
+		t.Fatal("app profile not exported")
+	}
+}
+
+func TestAppProfileSync(t *testing.T) {
+	type qeSync struct {
+		appID, capabilities, sigKeyVersion, signedBody string
+	}
+	var syncs []qeSync
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/"+urlQeSync {
+			r.ParseForm()
+			syncs = append(syncs, qeSync{
+				appID:         r.Header.Get("X-IG-App-ID"),
+				capabilities:  r.Header.Get("X-IG-Capabilities"),
+				sigKeyVersion: r.PostForm.Get("ig_sig_key_version"),
+				signedBody:    r.PostForm.Get("signed_body"),
+			})
+		}
+		w.Write([]byte(`{"status":"ok","logged_in_user":{"pk":1,"username":"user"}}`))
+	}))
+	defer srv.Close()
+
+	p := DefaultAppProfile()
+	p.AppID = "123"
+	p.Capabilities = "caps"
+	p.SigKey = "key"
+	p.SigKeyVersion = "5"
+	p.Experiments = "ig_first,ig_second"
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithAppProfile(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.syncFeatures(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(syncs) != 2 {
+		t.Fatalf("got %d %s requests, want one at login and one after", len(syncs), urlQeSync)
+	}
+	for _, s := range syncs {
+		if s.appID != p.AppID || s.capabilities != p.Capabilities || s.sigKeyVersion != p.SigKeyVersion {
+			t.Fatalf("sync not sent with the session profile: %+v", s)
+		}
+		i := strings.Index(s.signedBody, ".")
+		if i < 0 {
+			t.Fatalf("got signed body %q", s.signedBody)
+		}
+		data := s.signedBody[i+1:]
+		if s.signedBody[:i] != generateHMAC(data, p.SigKey) {
+			t.Fatalf("sync not signed with the profile key: %q", s.signedBody)
+		}
+		var body struct {
+			Experiments string `json:"experiments"`
+		}
+		if err := json.Unmarshal([]byte(data), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Experiments != p.Experiments {
+			t.Fatalf("got experiments %q, want the profile ones", body.Experiments)
+		}
+	}
+}
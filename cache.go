@@ -0,0 +1,222 @@
+package goinsta
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore stores API responses. See Cache.
+//
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the value of key if it has not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl.
+	Set(key string, value []byte, ttl time.Duration)
+	// InvalidatePrefix removes the values whose key starts with prefix.
+	InvalidatePrefix(prefix string)
+}
+
+// DefaultCacheTTLs returns the time to live of cached responses by endpoint,
+// with ids replaced by ":id" and names by ":name".
+func DefaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		"users/:name/usernameinfo/": 10 * time.Minute,
+		"users/:id/info/":           10 * time.Minute,
+		"tags/:name/info/":          10 * time.Minute,
+		"media/:id/info/":           5 * time.Minute,
+	}
+}
+
+// Cache returns an interceptor answering calls to the endpoints of ttls
+// from store. ttls is keyed like DefaultCacheTTLs, which is used when ttls is nil.
+//
+// Successful calls changing users, media or the account remove the related
+// responses from store. Responses are cached by viewer, since they depend on
+// the account (e.g. friendship_status or has_liked), so a store can be
+// shared between sessions.
+//
+//	insta.Use(goinsta.Cache(goinsta.NewLRUCache(1000), nil))
+func Cache(store CacheStore, ttls map[string]time.Duration) Interceptor {
+	if ttls == nil {
+		ttls = DefaultCacheTTLs()
+	}
+	return func(ctx context.Context, call *Call, next Handler) error {
+		ttl, ok := ttls[endpointLabel(call.Endpoint)]
+		if !ok {
+			err := next(ctx, call)
+			if err == nil && call.Method == "POST" {
+				for _, prefix := range invalidatedBy(call.Endpoint) {
+					store.InvalidatePrefix(prefix)
+				}
+			}
+			return err
+		}
+
+		key := cacheKey(call)
+		if body, ok := store.Get(key); ok {
+			call.StatusCode, call.Body = 200, body
+			return nil
+		}
+		err := next(ctx, call)
+		if err == nil {
+			store.Set(key, call.Body, ttl)
+		}
+		return err
+	}
+}
+
+// volatileParams change between sessions or requests without changing the response.
+var volatileParams = map[string]bool{
+	"_csrftoken":         true,
+	"_uuid":              true,
+	"_uid":               true,
+	"guid":               true,
+	"device_id":          true,
+	"rank_token":         true,
+	"ig_sig_key_version": true,
+}
+
+// cacheKey returns the endpoint and the parameters of call without volatile
+// values, followed by the viewer.
+func cacheKey(call *Call) string {
+	vs := url.Values{}
+	for k, v := range call.Query {
+		switch {
+		case volatileParams[k]:
+		case k == "signed_body":
+			vs.Set(k, stableSignedBody(v))
+		default:
+			vs.Set(k, v)
+		}
+	}
+	key := call.Endpoint
+	if q := vs.Encode(); q != "" {
+		key += "?" + q
+	}
+	if call.UseV2 {
+		key += "#v2"
+	}
+	return key + "#" + strconv.FormatInt(call.viewer, 10)
+}
+
+// stableSignedBody returns the signed data of body without volatile values.
+func stableSignedBody(body string) string {
+	data := signedData(body)
+	if data == nil {
+		return body
+	}
+	for k := range data {
+		if volatileParams[k] {
+			delete(data, k)
+		}
+	}
+	b, _ := json.Marshal(data)
+	return string(b)
+}
+
+// invalidatedBy returns the prefixes of the cache keys a successful POST
+// to endpoint makes stale.
+func invalidatedBy(endpoint string) []string {
+	parts := strings.Split(endpoint, "/")
+	if len(parts) < 3 {
+		return nil
+	}
+	switch parts[0] {
+	case "media":
+		// media ids are sent with or without the owner id
+		pk := strings.SplitN(parts[1], "_", 2)[0]
+		return []string{"media/" + pk}
+	case "friendships":
+		// users are cached by id and by name
+		return []string{"users/", "friendships/show/" + parts[2] + "/"}
+	case "accounts":
+		return []string{"users/", "accounts/"}
+	}
+	return nil
+}
+
+// LRUCache is an in-memory CacheStore keeping the most recently used values.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache creates a LRUCache holding up to size values.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements CacheStore.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set implements CacheStore.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &lruEntry{key: key, value: value, expires: time.Now().Add(ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.ll.PushFront(e)
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+}
+
+// InvalidatePrefix implements CacheStore.
+func (c *LRUCache) InvalidatePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+		}
+	}
+}
+
+// Len returns the number of stored values, including expired ones.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
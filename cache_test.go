@@ -0,0 +1,90 @@
+package goinsta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass",
+		WithBaseURL(srv.URL),
+		WithInterceptors(Cache(NewLRUCache(10), nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	byName := &reqOptions{Endpoint: "users/goinsta/usernameinfo/"}
+
+	for i := 0; i < 3; i++ {
+		if _, err = insta.sendRequest(ctx, byName); err != nil {
+			t.Fatal(err)
+		}
+		insta.sendRequest(ctx, &reqOptions{Endpoint: urlTimeline})
+	}
+	if n := hits["/api/v1/users/goinsta/usernameinfo/"]; n != 1 {
+		t.Fatalf("user fetched %d times, want 1", n)
+	}
+	if n := hits["/api/v1/feed/timeline/"]; n != 3 {
+		t.Fatalf("timeline fetched %d times, want 3", n)
+	}
+
+	// following a user invalidates cached users
+	insta.sendRequest(ctx, &reqOptions{Endpoint: "friendships/create/1/", IsPost: true})
+	insta.sendRequest(ctx, byName)
+	if n := hits["/api/v1/users/goinsta/usernameinfo/"]; n != 2 {
+		t.Fatalf("user fetched %d times after follow, want 2", n)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", []byte("a"), time.Minute)
+	c.Set("b", []byte("b"), time.Minute)
+	c.Get("a")
+	c.Set("c", []byte("c"), time.Minute)
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("least recently used value kept")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("recently used value evicted")
+	}
+
+	c.Set("d", []byte("d"), -time.Second)
+	if _, ok := c.Get("d"); ok {
+		t.Fatal("expired value returned")
+	}
+
+	c.InvalidatePrefix("a")
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Fatalf("invalidated value kept, %d values", c.Len())
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	insta := New("user", "pass")
+	data, _ := insta.prepareData(map[string]interface{}{"id": "1"})
+	a := cacheKey(&Call{Endpoint: "media/1/info/", Query: insta.generateSignature(data)})
+	insta.token.Set("other")
+	data, _ = insta.prepareData(map[string]interface{}{"id": "1"})
+	b := cacheKey(&Call{Endpoint: "media/1/info/", Query: insta.generateSignature(data)})
+	if a != b {
+		t.Fatalf("cache key depends on the csrf token: %s != %s", a, b)
+	}
+
+	// responses depend on the viewer
+	c := cacheKey(&Call{Endpoint: "media/1/info/", Query: insta.generateSignature(data), viewer: 2})
+	if c == b {
+		t.Fatalf("cache key does not depend on the viewer: %s", c)
+	}
+}
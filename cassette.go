@@ -0,0 +1,359 @@
+package goinsta
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Interaction is a request and its response, as stored in a cassette.
+//
+// A cassette is a file with one JSON encoded Interaction per line.
+type Interaction struct {
+	Method string `json:"method"`
+	// Path is the URL path, without host.
+	Path string `json:"path"`
+	// Params are the URL and form parameters. Values changing with the
+	// session are removed and secrets are redacted. The data of signed
+	// bodies is stored as JSON under signed_body.
+	Params      map[string]string `json:"params,omitempty"`
+	Status      int               `json:"status"`
+	ContentType string            `json:"content_type,omitempty"`
+	// Body is the response body. Secrets and contact points of JSON
+	// bodies are redacted.
+	Body string `json:"body"`
+}
+
+// key identifies the requests answered by i.
+func (i *Interaction) key() string {
+	vs := url.Values{}
+	for k, v := range i.Params {
+		vs.Set(k, v)
+	}
+	return i.Method + " " + i.Path + "?" + vs.Encode()
+}
+
+// requestParams returns the sanitized parameters of req, whose body is body.
+func requestParams(req *http.Request, body []byte) (map[string]string, error) {
+	vs := req.URL.Query()
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		r, err := decodeBody(bytes.NewReader(body), req.Header.Get("Content-Encoding"))
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		form, err := url.ParseQuery(string(b))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range form {
+			vs[k] = append(vs[k], v...)
+		}
+	}
+
+	params := make(map[string]string)
+	for k := range vs {
+		v := vs.Get(k)
+		switch {
+		case volatileParams[k] || isUUID(v):
+		case k == "signed_body":
+			data := signedData(v)
+			for dk, dv := range data {
+				s, _ := dv.(string)
+				switch {
+				case volatileParams[dk] || isUUID(s):
+					delete(data, dk)
+				case sensitiveKeys[dk]:
+					data[dk] = redacted
+				}
+			}
+			b, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			params[k] = string(b)
+		case sensitiveKeys[k]:
+			params[k] = redacted
+		default:
+			params[k] = v
+		}
+	}
+	return params, nil
+}
+
+// sensitiveFields are the response fields redacted in cassettes, in
+// addition to sensitiveKeys.
+var sensitiveFields = map[string]bool{
+	"email":                   true,
+	"public_email":            true,
+	"phone_number":            true,
+	"public_phone_number":     true,
+	"contact_phone_number":    true,
+	"obfuscated_phone_number": true,
+	"contact_point":           true,
+	"two_factor_identifier":   true,
+	"fb_access_token":         true,
+	"big_blue_token":          true,
+	"google_oauth_token":      true,
+}
+
+// redactBody returns the JSON response body with the string values of
+// sensitive fields redacted. Other bodies are returned unchanged.
+func redactBody(body []byte) []byte {
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v interface{}
+	if d.Decode(&v) != nil || !redactJSON(v) {
+		return body
+	}
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if e.Encode(v) != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// redactJSON redacts the decoded JSON value v in place and reports
+// whether it changed.
+func redactJSON(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			key := strings.ToLower(k)
+			if s, ok := value.(string); ok && s != "" && (sensitiveKeys[key] || sensitiveFields[key]) {
+				v[k] = redacted
+				changed = true
+				continue
+			}
+			if redactJSON(value) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactJSON(value) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// isUUID reports whether s is an UUID, like the identifiers generated by New.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := range s {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if s[i] != '-' {
+				return false
+			}
+		case !strings.ContainsRune("0123456789abcdefABCDEF", rune(s[i])):
+			return false
+		}
+	}
+	return true
+}
+
+// Recorder is a http.RoundTripper writing the interactions it sends to
+// a cassette. Responses are decoded, their secrets and contact points are
+// redacted as in the requests, and cookies are not recorded. The response
+// returned to the caller is not redacted.
+//
+//	f, _ := os.Create("testdata/session.jsonl")
+//	insta.SetHTTPTransport(goinsta.NewRecorder(f, nil))
+type Recorder struct {
+	mu   sync.Mutex
+	w    io.Writer
+	next http.RoundTripper
+}
+
+// NewRecorder creates a Recorder writing to w and sending requests with next,
+// or http.DefaultTransport if next is nil.
+func NewRecorder(w io.Writer, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{w: w, next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var payload []byte
+	if req.Body != nil {
+		var err error
+		payload, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(payload))
+	}
+	params, err := requestParams(req, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rec.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, _, err := readBody(resp)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(body))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	line, err := json.Marshal(&Interaction{
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		Params:      params,
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(redactBody(body)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if _, err = rec.w.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is a http.RoundTripper answering requests from a cassette,
+// without network access.
+//
+// Requests are matched by method, path and parameters. Interactions
+// recorded for the same request are replayed in order, and the last one
+// is repeated afterwards. Unknown requests fail.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string]*replayQueue
+}
+
+type replayQueue struct {
+	interactions []*Interaction
+	// replayed is the number of requests answered
+	replayed int
+}
+
+// NewReplayer reads a cassette from r.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rep := &Replayer{interactions: make(map[string]*replayQueue)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		i := &Interaction{}
+		if err := json.Unmarshal(line, i); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %v", n, err)
+		}
+		key := i.key()
+		q, ok := rep.interactions[key]
+		if !ok {
+			q = &replayQueue{}
+			rep.interactions[key] = q
+		}
+		q.interactions = append(q.interactions, i)
+	}
+	return rep, scanner.Err()
+}
+
+// LoadCassette reads the cassette stored at path. See NewReplayer.
+func LoadCassette(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewReplayer(f)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rep *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var payload []byte
+	if req.Body != nil {
+		var err error
+		payload, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	params, err := requestParams(req, payload)
+	if err != nil {
+		return nil, err
+	}
+	key := (&Interaction{Method: req.Method, Path: req.URL.Path, Params: params}).key()
+
+	rep.mu.Lock()
+	q, ok := rep.interactions[key]
+	if !ok {
+		rep.mu.Unlock()
+		return nil, fmt.Errorf("no recorded interaction for %s", key)
+	}
+	i := q.interactions[len(q.interactions)-1]
+	if q.replayed < len(q.interactions) {
+		i = q.interactions[q.replayed]
+	}
+	q.replayed++
+	rep.mu.Unlock()
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(strings.NewReader(i.Body)),
+		ContentLength: int64(len(i.Body)),
+		Request:       req,
+	}
+	if i.ContentType != "" {
+		resp.Header.Set("Content-Type", i.ContentType)
+	}
+	return resp, nil
+}
+
+// Pending returns the keys of the interactions never replayed, sorted.
+func (rep *Replayer) Pending() []string {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	var keys []string
+	for key, q := range rep.interactions {
+		if q.replayed < len(q.interactions) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
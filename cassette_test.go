@@ -0,0 +1,74 @@
+package goinsta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: "secret-session"})
+		w.Write([]byte(`{"status":"ok","path":"` + r.URL.Path + `",` +
+			`"logged_in_user":{"pk":1,"email":"secret@example.com","phone_number":"+33 secret"},"token":"secret-token"}`))
+	}))
+	defer srv.Close()
+
+	var cassette bytes.Buffer
+	insta, err := NewWithOptions("user", "secret-password",
+		WithBaseURL(srv.URL),
+		WithTransport(NewRecorder(&cassette, nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+	item := &Item{ID: "1_2", media: &FeedMedia{inst: insta}}
+	if err = item.Like(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(cassette.String(), "secret") {
+		t.Fatalf("secret recorded in cassette:\n%s", cassette.String())
+	}
+	if insta.Account.Email != "secret@example.com" {
+		t.Fatalf("recorded response redacted for the caller: %q", insta.Account.Email)
+	}
+
+	// another session, with other identifiers and no network, gets the same answers
+	replayer, err := NewReplayer(&cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	insta, err = NewWithOptions("user", "other-password",
+		WithBaseURL("http://127.0.0.1:1"),
+		WithTransport(replayer),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+	item = &Item{ID: "1_2", media: &FeedMedia{inst: insta}}
+	if err = item.Like(); err != nil {
+		t.Fatal(err)
+	}
+	if pending := replayer.Pending(); len(pending) != 0 {
+		t.Fatalf("interactions not replayed: %v", pending)
+	}
+
+	item = &Item{ID: "3_4", media: &FeedMedia{inst: insta}}
+	if err = item.Like(); !errors.Is(err, ErrNetwork) {
+		t.Fatalf("got %v for an unknown request, want ErrNetwork", err)
+	}
+	_, err = insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline})
+	if err == nil {
+		t.Fatal("unknown request replayed")
+	}
+}
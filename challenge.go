@@ -1,21 +1,64 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"strings"
 )
 
+// Choices of the select_verify_method step.
+const (
+	// ChallengeChoicePhone sends the security code by SMS.
+	ChallengeChoicePhone = "0"
+	// ChallengeChoiceEmail sends the security code by email.
+	ChallengeChoiceEmail = "1"
+)
+
+// maxChallengeSteps bounds the steps of a challenge, in case instagram
+// keeps asking for the same step.
+const maxChallengeSteps = 10
+
+var (
+	// ErrChallengeResend is returned by ChallengeSolver.SecurityCode to send
+	// the security code again before asking for it.
+	ErrChallengeResend = errors.New("resend the security code")
+	// ErrNoChallenge is returned by Solve and ResendCode when no challenge
+	// is in progress.
+	ErrNoChallenge = errors.New("no challenge in progress")
+)
+
+// ChallengeStepData is the data shown by instagram at a challenge step.
+// Which fields are set depends on Challenge.StepName.
 type ChallengeStepData struct {
-	Choice           string      `json:"choice"`
-	FbAccessToken    string      `json:"fb_access_token"`
-	BigBlueToken     string      `json:"big_blue_token"`
-	GoogleOauthToken string      `json:"google_oauth_token"`
-	Email            string      `json:"email"`
-	SecurityCode     string      `json:"security_code"`
-	ResendDelay      interface{} `json:"resend_delay"`
-	ContactPoint     string      `json:"contact_point"`
-	FormType         string      `json:"form_type"`
+	// Choice is the verify method selected by default, at the
+	// select_verify_method step.
+	Choice string `json:"choice"`
+	// FbAccessToken, BigBlueToken and GoogleOauthToken are set when the
+	// account can be verified with Facebook or Google instead.
+	FbAccessToken    string `json:"fb_access_token"`
+	BigBlueToken     string `json:"big_blue_token"`
+	GoogleOauthToken string `json:"google_oauth_token"`
+	// Email and PhoneNumber are the obfuscated contact points offered at
+	// the select_verify_method step. At the submit_email and submit_phone
+	// steps, they hold the value to replace, if any.
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	// SecurityCode is the code typed so far, at the verify steps. It is
+	// usually empty.
+	SecurityCode string `json:"security_code"`
+	// ResendDelay, ContactPoint and FormType describe the code sent at the
+	// verify_phone, verify_email and verify_code steps: the seconds before
+	// it can be sent again, where it was sent and whether it was sent to
+	// a "phone_number" or an "email".
+	ResendDelay  interface{} `json:"resend_delay"`
+	ContactPoint string      `json:"contact_point"`
+	FormType     string      `json:"form_type"`
 }
+
+// Challenge is the security challenge of the session, at its current step.
+//
+// Its methods update the challenge: like the iterators, it must be used by
+// one goroutine at a time.
 type Challenge struct {
 	insta        *Instagram
 	StepName     string            `json:"step_name"`
@@ -31,6 +74,29 @@ type challengeResp struct {
 	*Challenge
 }
 
+// ChallengeSolver answers the steps of a challenge for the application,
+// e.g. by asking the user. See Challenge.Solve.
+//
+// The methods receive the challenge at the current step: StepData holds
+// the contact points shown by instagram.
+type ChallengeSolver interface {
+	// VerifyMethod chooses where the security code is sent,
+	// ChallengeChoicePhone or ChallengeChoiceEmail.
+	VerifyMethod(ctx context.Context, challenge *Challenge) (string, error)
+	// SecurityCode returns the code sent to StepData.ContactPoint.
+	// It can return ErrChallengeResend to receive a new code.
+	SecurityCode(ctx context.Context, challenge *Challenge) (string, error)
+	// PhoneNumber returns the phone number to add to the account.
+	PhoneNumber(ctx context.Context, challenge *Challenge) (string, error)
+	// Email returns the email to add to the account.
+	Email(ctx context.Context, challenge *Challenge) (string, error)
+	// NewPassword returns the password replacing the current one.
+	NewPassword(ctx context.Context, challenge *Challenge) (string, error)
+	// ConfirmLogin reports whether the user made the reviewed login
+	// ("This was me").
+	ConfirmLogin(ctx context.Context, challenge *Challenge) (bool, error)
+}
+
 func newChallenge(insta *Instagram) *Challenge {
 	time := &Challenge{
 		insta: insta,
@@ -38,121 +104,272 @@ func newChallenge(insta *Instagram) *Challenge {
 	return time
 }
 
+// update replaces the challenge with the one in the response body.
+func (challenge *Challenge) update(body []byte) error {
+	insta := challenge.insta
+	resp := challengeResp{}
+	err := insta.unmarshal(body, &resp)
+	if err == nil {
+		if resp.Challenge != nil {
+			*challenge = *resp.Challenge
+		} else {
+			// the answer only acknowledges the step
+			*challenge = Challenge{}
+		}
+		challenge.insta = insta
+		insta.logger().Info("challenge step", "step", challenge.StepName, "status", challenge.Status)
+	}
+	return err
+}
+
 // updateState updates current data from challenge url
-func (challenge *Challenge) updateState() error {
+func (challenge *Challenge) updateState(ctx context.Context) error {
 	insta := challenge.insta
 
 	data, err := insta.prepareData(map[string]interface{}{
-		"guid":      insta.uuid,
-		"device_id": insta.dID,
+		"guid":      insta.uuid.Get(),
+		"device_id": insta.dID.Get(),
 	})
 	if err != nil {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: challenge.insta.challengeURL,
-			Query:    generateSignature(data),
+			Endpoint: challenge.insta.challengeURL.Get(),
+			Query:    insta.generateSignature(data),
 		},
 	)
 	if err == nil {
-		resp := challengeResp{}
-		err = json.Unmarshal(body, &resp)
-		if err == nil {
-			*challenge = *resp.Challenge
-			challenge.insta = insta
-		}
+		err = challenge.update(body)
 	}
 	return err
 }
 
-// selectVerifyMethod selects a way and verify it (Phone number = 0, email = 1)
-func (challenge *Challenge) selectVerifyMethod(choice string, isReplay ...bool) error {
+// post sends the answer of the current step, to the replay URL if
+// isReplay is true.
+func (challenge *Challenge) post(ctx context.Context, answer map[string]interface{}, isReplay ...bool) error {
 	insta := challenge.insta
 
-	url := challenge.insta.challengeURL
+	url := challenge.insta.challengeURL.Get()
 	if len(isReplay) > 0 && isReplay[0] {
-		url = strings.Replace(url, "/challenge/", "/challenge/replay/", -1)
+		url = strings.Replace(url, "challenge/", "challenge/replay/", 1)
 	}
 
-	data, err := insta.prepareData(map[string]interface{}{
-		"choice":    choice,
-		"guid":      insta.uuid,
-		"device_id": insta.dID,
-	})
+	answer["guid"] = insta.uuid.Get()
+	answer["device_id"] = insta.dID.Get()
+	data, err := insta.prepareData(answer)
 	if err != nil {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: url,
-			Query:    generateSignature(data),
-			IsPost:   true,
+			Endpoint:  url,
+			Query:     insta.generateSignature(data),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
 		},
 	)
 	if err == nil {
-		resp := challengeResp{}
-		err = json.Unmarshal(body, &resp)
+		err = challenge.update(body)
 		if err == nil {
-			*challenge = *resp.Challenge
-			challenge.insta = insta
+			challenge.loggedIn()
 		}
 	}
 	return err
 }
 
+// selectVerifyMethod selects a way and verify it (Phone number = 0, email = 1)
+func (challenge *Challenge) selectVerifyMethod(ctx context.Context, choice string, isReplay ...bool) error {
+	return challenge.post(ctx, map[string]interface{}{"choice": choice}, isReplay...)
+}
+
 // sendSecurityCode sends the code received in the message
 func (challenge *Challenge) SendSecurityCode(code string) error {
-	insta := challenge.insta
-	url := challenge.insta.challengeURL
+	return challenge.SendSecurityCodeContext(context.Background(), code)
+}
 
-	data, err := insta.prepareData(map[string]interface{}{
-		"security_code": code,
-		"guid":          insta.uuid,
-		"device_id":     insta.dID,
-	})
-	if err != nil {
-		return err
+// SendSecurityCodeContext is the context-aware version of SendSecurityCode.
+func (challenge *Challenge) SendSecurityCodeContext(ctx context.Context, code string) error {
+	return challenge.post(ctx, map[string]interface{}{"security_code": strings.Replace(code, " ", "", -1)})
+}
+
+// ResendCode sends the security code of the current verify step again.
+func (challenge *Challenge) ResendCode() error {
+	return challenge.ResendCodeContext(context.Background())
+}
+
+// ResendCodeContext is the context-aware version of ResendCode.
+func (challenge *Challenge) ResendCodeContext(ctx context.Context) error {
+	if challenge.insta.challengeURL.Get() == "" {
+		return ErrNoChallenge
 	}
+	choice := challenge.StepData.Choice
+	switch {
+	case challenge.StepName == "verify_phone", challenge.StepData.FormType == "phone_number":
+		choice = ChallengeChoicePhone
+	case challenge.StepName == "verify_email", challenge.StepData.FormType == "email":
+		choice = ChallengeChoiceEmail
+	}
+	return challenge.selectVerifyMethod(ctx, choice, true)
+}
 
-	body, err := insta.sendRequest(
-		&reqOptions{
-			Endpoint: url,
-			Query:    generateSignature(data),
-			IsPost:   true,
-		},
-	)
-	if err == nil {
-		resp := challengeResp{}
-		err = json.Unmarshal(body, &resp)
-		if err == nil {
-			*challenge = *resp.Challenge
-			challenge.insta = insta
-		}
+// loggedIn adopts the account logged in by a solved challenge.
+func (challenge *Challenge) loggedIn() {
+	account := challenge.LoggedInUser
+	if account == nil {
+		return
 	}
-	return err
+	insta := challenge.insta
+	insta.setAccount(account)
+	insta.challengeURL.Set("")
+	insta.logger().Info("logged in by challenge", "user", insta.user)
+	insta.autoSave("challenge")
+}
+
+// closed reports whether instagram ended the challenge.
+func (challenge *Challenge) closed() bool {
+	return challenge.LoggedInUser != nil || challenge.Action == "close"
 }
 
 // deltaLoginReview process with choice (It was me = 0, It wasn't me = 1)
-func (challenge *Challenge) deltaLoginReview() error {
-	return challenge.selectVerifyMethod("0")
+func (challenge *Challenge) deltaLoginReview(ctx context.Context) error {
+	return challenge.selectVerifyMethod(ctx, "0")
 }
 
+// Process starts the challenge at apiURL, the api_path of a ChallengeError.
+//
+// It sends the security code to the default contact point and confirms
+// reviewed logins, then returns: send the code with SendSecurityCode.
+// Use Solve for the other steps.
 func (challenge *Challenge) Process(apiURL string) error {
-	challenge.insta.challengeURL = apiURL[1:]
+	return challenge.ProcessContext(context.Background(), apiURL)
+}
 
-	if err := challenge.updateState(); err != nil {
+// ProcessContext is the context-aware version of Process.
+func (challenge *Challenge) ProcessContext(ctx context.Context, apiURL string) error {
+	challenge.start(apiURL)
+
+	if err := challenge.updateState(ctx); err != nil {
 		return err
 	}
 
 	switch challenge.StepName {
 	case "select_verify_method":
-		return challenge.selectVerifyMethod(challenge.StepData.Choice)
+		return challenge.selectVerifyMethod(ctx, challenge.StepData.Choice)
 	case "delta_login_review":
-		return challenge.deltaLoginReview()
+		return challenge.deltaLoginReview(ctx)
+	}
+
+	challenge.insta.logger().Warn("unsupported challenge step", "step", challenge.StepName)
+	return ErrChallengeProcess{StepName: challenge.StepName}
+}
+
+// start records the challenge at apiURL. The challenge URL is saved with
+// the session so that the challenge can be solved after an import.
+func (challenge *Challenge) start(apiURL string) {
+	insta := challenge.insta
+	insta.challengeURL.Set(strings.TrimPrefix(apiURL, "/"))
+	insta.autoSave("challenge")
+}
+
+// Solve solves the challenge in progress with solver, step by step, until
+// instagram logs the session in or closes the challenge.
+//
+// The challenge in progress is the one of the last ChallengeError returned
+// by Login, or the one of an imported session.
+func (challenge *Challenge) Solve(solver ChallengeSolver) error {
+	return challenge.SolveContext(context.Background(), solver)
+}
+
+// SolveContext is the context-aware version of Solve.
+func (challenge *Challenge) SolveContext(ctx context.Context, solver ChallengeSolver) error {
+	if challenge.insta.challengeURL.Get() == "" {
+		return ErrNoChallenge
+	}
+	if err := challenge.updateState(ctx); err != nil {
+		return err
+	}
+	for i := 0; i < maxChallengeSteps; i++ {
+		if challenge.closed() {
+			// replace the session saved with the challenge URL, even
+			// if the challenge did not log in
+			challenge.insta.challengeURL.Set("")
+			challenge.insta.storeSession("challenge")
+			return nil
+		}
+		var err error
+		if challenge.StepName == "" {
+			// some answers only acknowledge the step
+			err = challenge.updateState(ctx)
+		} else {
+			err = challenge.step(ctx, solver)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return ErrChallengeProcess{StepName: challenge.StepName}
+}
+
+// step answers the current step with solver.
+func (challenge *Challenge) step(ctx context.Context, solver ChallengeSolver) error {
+	switch challenge.StepName {
+	case "select_verify_method":
+		choice, err := solver.VerifyMethod(ctx, challenge)
+		if err != nil {
+			return err
+		}
+		return challenge.selectVerifyMethod(ctx, choice)
+
+	case "verify_phone", "verify_email", "verify_code":
+		code, err := solver.SecurityCode(ctx, challenge)
+		if errors.Is(err, ErrChallengeResend) {
+			return challenge.ResendCodeContext(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		return challenge.SendSecurityCodeContext(ctx, code)
+
+	case "submit_phone":
+		phone, err := solver.PhoneNumber(ctx, challenge)
+		if err != nil {
+			return err
+		}
+		return challenge.post(ctx, map[string]interface{}{"phone_number": phone})
+
+	case "submit_email":
+		email, err := solver.Email(ctx, challenge)
+		if err != nil {
+			return err
+		}
+		return challenge.post(ctx, map[string]interface{}{"email": email})
+
+	case "change_password", "reset_password":
+		password, err := solver.NewPassword(ctx, challenge)
+		if err != nil {
+			return err
+		}
+		return challenge.post(ctx, map[string]interface{}{
+			"new_password1": password,
+			"new_password2": password,
+		})
+
+	case "delta_login_review":
+		me, err := solver.ConfirmLogin(ctx, challenge)
+		if err != nil {
+			return err
+		}
+		choice := "1"
+		if me {
+			choice = "0"
+		}
+		return challenge.selectVerifyMethod(ctx, choice)
 	}
 
+	challenge.insta.logger().Warn("unsupported challenge step", "step", challenge.StepName)
 	return ErrChallengeProcess{StepName: challenge.StepName}
 }
@@ -0,0 +1,27 @@
+package goinsta
+
+import "testing"
+
+func TestChallengeUpdateEmpty(t *testing.T) {
+	insta := New("user", "pass")
+	challenge := insta.Challenge
+	challenge.StepName = "verify_email"
+	challenge.StepData.ContactPoint = "b***@example.com"
+
+	if err := challenge.update([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if challenge.StepName != "" || challenge.StepData.ContactPoint != "" {
+		t.Fatalf("previous step kept: %+v", challenge)
+	}
+	if challenge.insta != insta {
+		t.Fatal("challenge detached from the session")
+	}
+
+	if err := challenge.update([]byte(`{"step_name":"submit_phone","step_data":{"phone_number":"+33600000000"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if challenge.StepName != "submit_phone" || challenge.StepData.PhoneNumber != "+33600000000" {
+		t.Fatalf("got step %+v", challenge)
+	}
+}
@@ -1,6 +1,7 @@
 package goinsta
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -34,7 +35,7 @@ type Comments struct {
 
 func (comments *Comments) setValues() {
 	for i := range comments.Items {
-		comments.Items[i].setValues(comments.item.media.instagram())
+		comments.Items[i].setValues(comments.item.media.Instagram())
 	}
 }
 
@@ -53,13 +54,18 @@ func (comments Comments) Error() error {
 //
 // See example: examples/media/commentDisable.go
 func (comments *Comments) Disable() error {
+	return comments.DisableContext(context.Background())
+}
+
+// DisableContext is the context-aware version of Disable.
+func (comments *Comments) DisableContext(ctx context.Context) error {
 	switch comments.item.media.(type) {
 	case *StoryMedia:
 		return fmt.Errorf("Incompatible type. Cannot use Disable() with StoryMedia type")
 	default:
 	}
 
-	insta := comments.item.media.instagram()
+	insta := comments.item.media.Instagram()
 	data, err := insta.prepareData(
 		map[string]interface{}{
 			"media_id": comments.item.ID,
@@ -69,10 +75,10 @@ func (comments *Comments) Disable() error {
 		return err
 	}
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentDisable, comments.item.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -83,13 +89,18 @@ func (comments *Comments) Disable() error {
 //
 // See example: examples/media/commentEnable.go
 func (comments *Comments) Enable() error {
+	return comments.EnableContext(context.Background())
+}
+
+// EnableContext is the context-aware version of Enable.
+func (comments *Comments) EnableContext(ctx context.Context) error {
 	switch comments.item.media.(type) {
 	case *StoryMedia:
 		return fmt.Errorf("Incompatible type. Cannot use Enable() with StoryMedia type")
 	default:
 	}
 
-	insta := comments.item.media.instagram()
+	insta := comments.item.media.Instagram()
 	data, err := insta.prepareData(
 		map[string]interface{}{
 			"media_id": comments.item.ID,
@@ -99,10 +110,10 @@ func (comments *Comments) Enable() error {
 		return err
 	}
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentEnable, comments.item.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -115,12 +126,17 @@ func (comments *Comments) Enable() error {
 //
 // New comments are stored in Comments.Items
 func (comments *Comments) Next() bool {
+	return comments.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (comments *Comments) NextContext(ctx context.Context) bool {
 	if comments.err != nil {
 		return false
 	}
 
 	item := comments.item
-	insta := item.media.instagram()
+	insta := item.media.Instagram()
 	endpoint := comments.endpoint
 	query := map[string]string{
 		// "can_support_threading": "true",
@@ -133,7 +149,7 @@ func (comments *Comments) Next() bool {
 		query["min_id"] = next
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint:   endpoint,
 			Connection: "keep-alive",
@@ -142,7 +158,7 @@ func (comments *Comments) Next() bool {
 	)
 	if err == nil {
 		c := Comments{}
-		err = json.Unmarshal(body, &c)
+		err = insta.unmarshal(body, &c)
 		if err == nil {
 			*comments = c
 			comments.endpoint = endpoint
@@ -176,9 +192,14 @@ func (comments *Comments) Sync() {
 //
 // See example: examples/media/commentsAdd.go
 func (comments *Comments) Add(text string) (err error) {
+	return comments.AddContext(context.Background(), text)
+}
+
+// AddContext is the context-aware version of Add.
+func (comments *Comments) AddContext(ctx context.Context, text string) (err error) {
 	var opt *reqOptions
 	item := comments.item
-	insta := item.media.instagram()
+	insta := item.media.Instagram()
 
 	switch item.media.(type) {
 	case *StoryMedia:
@@ -213,7 +234,7 @@ func (comments *Comments) Add(text string) (err error) {
 		)
 		opt = &reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentAdd, item.Pk),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		}
 	}
@@ -222,13 +243,18 @@ func (comments *Comments) Add(text string) (err error) {
 	}
 
 	// ignoring response
-	_, err = insta.sendRequest(opt)
+	_, err = insta.sendRequest(ctx, opt)
 	return err
 }
 
 // Del deletes comment.
 func (comments *Comments) Del(comment *Comment) error {
-	insta := comments.item.media.instagram()
+	return comments.DelContext(context.Background(), comment)
+}
+
+// DelContext is the context-aware version of Del.
+func (comments *Comments) DelContext(ctx context.Context, comment *Comment) error {
+	insta := comments.item.media.Instagram()
 
 	data, err := insta.prepareData()
 	if err != nil {
@@ -236,10 +262,10 @@ func (comments *Comments) Del(comment *Comment) error {
 	}
 	id := comment.getid()
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentDelete, comments.item.ID, id),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -250,7 +276,12 @@ func (comments *Comments) Del(comment *Comment) error {
 //
 // See example: examples/media/commentsDelByID.go
 func (comments *Comments) DelByID(id string) error {
-	return comments.Del(&Comment{idstr: id})
+	return comments.DelByIDContext(context.Background(), id)
+}
+
+// DelByIDContext is the context-aware version of DelByID.
+func (comments *Comments) DelByIDContext(ctx context.Context, id string) error {
+	return comments.DelContext(ctx, &Comment{idstr: id})
 }
 
 // DelMine removes all of your comments limited by parsed parameter.
@@ -259,21 +290,26 @@ func (comments *Comments) DelByID(id string) error {
 //
 // See example: examples/media/commentsDelMine.go
 func (comments *Comments) DelMine(limit int) error {
+	return comments.DelMineContext(context.Background(), limit)
+}
+
+// DelMineContext is the context-aware version of DelMine.
+func (comments *Comments) DelMineContext(ctx context.Context, limit int) error {
 	i := 0
 	if limit <= 0 {
 		i = limit - 1
 	}
 	comments.Sync()
 
-	insta := comments.item.media.instagram()
+	insta := comments.item.media.Instagram()
 floop:
-	for comments.Next() {
+	for comments.NextContext(ctx) {
 		for _, c := range comments.Items {
-			if c.UserID == insta.Account.ID || c.User.ID == insta.Account.ID {
+			if id := insta.accountID(); c.UserID == id || c.User.ID == id {
 				if i >= limit {
 					break floop
 				}
-				comments.Del(&c)
+				comments.DelContext(ctx, &c)
 				i++
 			}
 		}
@@ -337,15 +373,20 @@ func (c Comment) getid() string {
 
 // Like likes comment.
 func (c *Comment) Like() error {
+	return c.LikeContext(context.Background())
+}
+
+// LikeContext is the context-aware version of Like.
+func (c *Comment) LikeContext(ctx context.Context) error {
 	data, err := c.inst.prepareData()
 	if err != nil {
 		return err
 	}
 
-	_, err = c.inst.sendRequest(
+	_, err = c.inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentLike, c.getid()),
-			Query:    generateSignature(data),
+			Query:    c.inst.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -354,15 +395,20 @@ func (c *Comment) Like() error {
 
 // Unlike unlikes comment.
 func (c *Comment) Unlike() error {
+	return c.UnlikeContext(context.Background())
+}
+
+// UnlikeContext is the context-aware version of Unlike.
+func (c *Comment) UnlikeContext(ctx context.Context) error {
 	data, err := c.inst.prepareData()
 	if err != nil {
 		return err
 	}
 
-	_, err = c.inst.sendRequest(
+	_, err = c.inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentUnlike, c.getid()),
-			Query:    generateSignature(data),
+			Query:    c.inst.generateSignature(data),
 			IsPost:   true,
 		},
 	)
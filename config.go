@@ -0,0 +1,65 @@
+package goinsta
+
+import (
+	"fmt"
+)
+
+// ConfigVersion is the version of the ConfigFile written by Export.
+//
+// Configs of older versions are migrated on import, see ConfigFile.Migrate.
+const ConfigVersion = 1
+
+// ProxyConfig is the proxy of a session. See SetProxy.
+type ProxyConfig struct {
+	// URL is the proxy URL, including its credentials if any.
+	URL      string `json:"url"`
+	Insecure bool   `json:"insecure,omitempty"`
+}
+
+// configMigrations upgrade the configs exported by older versions of
+// goinsta: configMigrations[v] migrates a config of version v to v+1.
+var configMigrations = []func(config *ConfigFile){
+	// 0 is every config written before the version field. Older ones
+	// lack the device, the app and the ad id.
+	fillConfigDefaults,
+}
+
+// fillConfigDefaults sets the missing state of config to the defaults.
+func fillConfigDefaults(config *ConfigFile) {
+	config.Hosts = config.Hosts.withDefaults()
+	if config.Device == nil {
+		device := DefaultDevice()
+		config.Device = &device
+	}
+	if config.App == nil {
+		app := DefaultAppProfile()
+		config.App = &app
+	}
+	if config.AdID == "" {
+		config.AdID = generateUUID()
+	}
+	if config.Locale == "" {
+		config.Locale = defaultLocale
+	}
+}
+
+// Migrate upgrades a config exported by an older version of goinsta to
+// ConfigVersion, filling the state it lacks with defaults. The state
+// missing from configs of the current version, e.g. edited by hand, is
+// filled too.
+//
+// ImportConfig migrates the configs it imports.
+func (config *ConfigFile) Migrate() error {
+	if config.Version > ConfigVersion {
+		return fmt.Errorf("config version %d is newer than the supported version %d", config.Version, ConfigVersion)
+	}
+	if config.Version < 0 {
+		return fmt.Errorf("invalid config version %d", config.Version)
+	}
+	for config.Version < ConfigVersion {
+		configMigrations[config.Version](config)
+		config.Version++
+	}
+	fillConfigDefaults(config)
+	return nil
+}
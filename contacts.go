@@ -1,6 +1,7 @@
 package goinsta
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 )
@@ -37,17 +38,22 @@ func newContacts(inst *Instagram) *Contacts {
 }
 
 func (c *Contacts) SyncContacts(contacts *[]Contact) (*SyncAnswer, error) {
+	return c.SyncContactsContext(context.Background(), contacts)
+}
+
+// SyncContactsContext is the context-aware version of SyncContacts.
+func (c *Contacts) SyncContactsContext(ctx context.Context, contacts *[]Contact) (*SyncAnswer, error) {
 	acquireContacts := &reqOptions{
 		Endpoint: "address_book/acquire_owner_contacts/",
 		IsPost:   true,
 		Login:    true,
 		UseV2:    false,
 		Query: map[string]string{
-			"phone_id": c.inst.pid,
+			"phone_id": c.inst.pid.Get(),
 			"me":       `{"phone_numbers":[],"email_addresses":[]}`,
 		},
 	}
-	body, err := c.inst.sendRequest(acquireContacts)
+	body, err := c.inst.sendRequest(ctx, acquireContacts)
 	if err != nil {
 		return nil, err
 	}
@@ -63,27 +69,32 @@ func (c *Contacts) SyncContacts(contacts *[]Contact) (*SyncAnswer, error) {
 		Login:    true,
 		UseV2:    false,
 		Query: map[string]string{
-			"_uuid":      c.inst.uuid,
-			"_csrftoken": c.inst.token,
+			"_uuid":      c.inst.uuid.Get(),
+			"_csrftoken": c.inst.token.Get(),
 			"contacts":   string(byteContacts),
 		},
 	}
 
-	body, err = c.inst.sendRequest(syncContacts)
+	body, err = c.inst.sendRequest(ctx, syncContacts)
 	if err != nil {
 		return nil, err
 	}
 
 	answ := &SyncAnswer{}
-	json.Unmarshal(body, answ)
+	c.inst.unmarshal(body, answ)
 	return answ, nil
 }
 
 func (c *Contacts) UnlinkContacts() error {
+	return c.UnlinkContactsContext(context.Background())
+}
+
+// UnlinkContactsContext is the context-aware version of UnlinkContacts.
+func (c *Contacts) UnlinkContactsContext(ctx context.Context) error {
 	toSign := map[string]string{
-		"_csrftoken": c.inst.token,
-		"_uid":       strconv.Itoa(int(c.inst.Account.ID)),
-		"_uuid":      c.inst.uuid,
+		"_csrftoken": c.inst.token.Get(),
+		"_uid":       strconv.FormatInt(c.inst.accountID(), 10),
+		"_uuid":      c.inst.uuid.Get(),
 	}
 
 	bytesS, _ := json.Marshal(toSign)
@@ -93,10 +104,10 @@ func (c *Contacts) UnlinkContacts() error {
 		IsPost:   true,
 		Login:    true,
 		UseV2:    false,
-		Query:    generateSignature(string(bytesS)),
+		Query:    c.inst.generateSignature(string(bytesS)),
 	}
 
-	_, err := c.inst.sendRequest(unlinkBody)
+	_, err := c.inst.sendRequest(ctx, unlinkBody)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,194 @@
+package goinsta
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted sessions are exported in an envelope made of a header followed
+// by the AES-256-GCM sealed session:
+//
+//	magic    "GOINSTA\x00" (8 bytes)
+//	version  1 (1 byte)
+//	kdf      0 for a raw key, 1 for scrypt (1 byte)
+//	log2 N   scrypt cost, 0 for a raw key (1 byte)
+//	r, p     scrypt parameters, 0 for a raw key (2 x 1 byte)
+//	salt     16 bytes, zero for a raw key
+//	nonce    12 bytes
+//	sealed session
+//
+// The header is authenticated as additional data.
+const (
+	envelopeMagic   = "GOINSTA\x00"
+	envelopeVersion = 1
+
+	// offsets of the header fields
+	offVersion     = len(envelopeMagic)
+	offKDF         = offVersion + 1
+	offLogN        = offKDF + 1
+	offR           = offLogN + 1
+	offP           = offR + 1
+	offSalt        = offP + 1
+	offNonce       = offSalt + 16
+	envelopeHeader = offNonce + 12
+
+	kdfRawKey = 0
+	kdfScrypt = 1
+
+	// scrypt parameters of new envelopes, N = 1<<scryptLogN.
+	scryptLogN = 15
+	scryptR    = 8
+	scryptP    = 1
+)
+
+var (
+	// ErrSessionKey means the key or passphrase cannot decrypt the session,
+	// or the session was modified.
+	ErrSessionKey = errors.New("invalid session key or corrupted session")
+	// ErrSessionEnvelope means the data is not an encrypted session
+	// supported by this version of goinsta.
+	ErrSessionEnvelope = errors.New("unsupported encrypted session")
+)
+
+// ExportEncrypted exports the session to writer encrypted with a key
+// derived from passphrase.
+//
+// The session holds the cookies of the account: keep the passphrase secret.
+func ExportEncrypted(inst *Instagram, writer io.Writer, passphrase string) error {
+	return exportEncrypted(inst, writer, kdfScrypt, []byte(passphrase))
+}
+
+// ExportEncryptedKey exports the session to writer encrypted with key,
+// which must be 32 random bytes.
+func ExportEncryptedKey(inst *Instagram, writer io.Writer, key []byte) error {
+	if len(key) != 32 {
+		return errors.New("session key must be 32 bytes long")
+	}
+	return exportEncrypted(inst, writer, kdfRawKey, key)
+}
+
+// ExportEncrypted exports the session to the file path encrypted with a
+// key derived from passphrase. The file is only readable by its owner.
+func (inst *Instagram) ExportEncrypted(path, passphrase string) error {
+	var buf bytes.Buffer
+	if err := ExportEncrypted(inst, &buf, passphrase); err != nil {
+		return err
+	}
+	return writeSessionFile(path, buf.Bytes())
+}
+
+func exportEncrypted(inst *Instagram, writer io.Writer, kdf byte, secret []byte) error {
+	var session bytes.Buffer
+	if err := Export(inst, &session); err != nil {
+		return err
+	}
+
+	header := make([]byte, envelopeHeader)
+	copy(header, envelopeMagic)
+	header[offVersion], header[offKDF] = envelopeVersion, kdf
+	if kdf == kdfScrypt {
+		header[offLogN], header[offR], header[offP] = scryptLogN, scryptR, scryptP
+		if _, err := rand.Read(header[offSalt:offNonce]); err != nil {
+			return err
+		}
+	}
+	nonce := header[offNonce:]
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	aead, err := envelopeCipher(header, secret)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(aead.Seal(header, nonce, session.Bytes(), header))
+	return err
+}
+
+// ImportEncrypted imports a session exported by ExportEncrypted.
+//
+// opts are applied as in ImportConfig.
+func ImportEncrypted(r io.Reader, passphrase string, opts ...Option) (*Instagram, error) {
+	return importEncrypted(r, kdfScrypt, []byte(passphrase), opts)
+}
+
+// ImportEncryptedKey imports a session exported by ExportEncryptedKey.
+func ImportEncryptedKey(r io.Reader, key []byte, opts ...Option) (*Instagram, error) {
+	return importEncrypted(r, kdfRawKey, key, opts)
+}
+
+// ImportEncryptedFile imports a session exported by Instagram.ExportEncrypted.
+func ImportEncryptedFile(path, passphrase string, opts ...Option) (*Instagram, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ImportEncrypted(f, passphrase, opts...)
+}
+
+func importEncrypted(r io.Reader, kdf byte, secret []byte, opts []Option) (*Instagram, error) {
+	envelope, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope) < envelopeHeader || string(envelope[:offVersion]) != envelopeMagic ||
+		envelope[offVersion] != envelopeVersion {
+		return nil, ErrSessionEnvelope
+	}
+	header := envelope[:envelopeHeader]
+	if header[offKDF] != kdf {
+		if kdf == kdfScrypt {
+			return nil, errors.New("session is encrypted with a key, not a passphrase")
+		}
+		return nil, errors.New("session is encrypted with a passphrase, not a key")
+	}
+
+	aead, err := envelopeCipher(header, secret)
+	if err != nil {
+		return nil, err
+	}
+	session, err := aead.Open(nil, header[offNonce:], envelope[envelopeHeader:], header)
+	if err != nil {
+		return nil, ErrSessionKey
+	}
+	return ImportReader(bytes.NewReader(session), opts...)
+}
+
+// envelopeCipher returns the cipher of the envelope header, deriving the
+// key from secret as the header says.
+func envelopeCipher(header, secret []byte) (cipher.AEAD, error) {
+	key := secret
+	switch header[offKDF] {
+	case kdfRawKey:
+		if len(key) != 32 {
+			return nil, errors.New("session key must be 32 bytes long")
+		}
+	case kdfScrypt:
+		// the header is only authenticated once the key is derived:
+		// never let it choose the cost of the derivation
+		if header[offLogN] != scryptLogN || header[offR] != scryptR || header[offP] != scryptP {
+			return nil, ErrSessionEnvelope
+		}
+		var err error
+		key, err = scrypt.Key(secret, header[offSalt:offNonce], 1<<scryptLogN, scryptR, scryptP, 32)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrSessionEnvelope
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
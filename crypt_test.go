@@ -0,0 +1,112 @@
+package goinsta
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportEncrypted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok","user":{"pk":1,"username":"user"}}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	insta.token.Set("csrf-secret")
+
+	var buf bytes.Buffer
+	if err := ExportEncrypted(insta, &buf, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	envelope := buf.Bytes()
+	if bytes.Contains(envelope, []byte("csrf-secret")) {
+		t.Fatal("session exported in clear")
+	}
+
+	imported, err := ImportEncrypted(bytes.NewReader(envelope), "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.token.Get() != "csrf-secret" || imported.dID.Get() != insta.dID.Get() {
+		t.Fatal("imported session differs")
+	}
+
+	if _, err := ImportEncrypted(bytes.NewReader(envelope), "wrong"); !errors.Is(err, ErrSessionKey) {
+		t.Fatalf("got %v with a wrong passphrase, want ErrSessionKey", err)
+	}
+	tampered := append([]byte(nil), envelope...)
+	tampered[offSalt] ^= 1
+	if _, err := ImportEncrypted(bytes.NewReader(tampered), "passphrase"); !errors.Is(err, ErrSessionKey) {
+		t.Fatalf("got %v with a modified header, want ErrSessionKey", err)
+	}
+	tampered = append([]byte(nil), envelope...)
+	tampered[offLogN] = 24
+	if _, err := ImportEncrypted(bytes.NewReader(tampered), "passphrase"); !errors.Is(err, ErrSessionEnvelope) {
+		t.Fatalf("got %v with a modified scrypt cost, want ErrSessionEnvelope", err)
+	}
+	tampered = append([]byte(nil), envelope...)
+	tampered[offVersion] = 2
+	if _, err := ImportEncrypted(bytes.NewReader(tampered), "passphrase"); !errors.Is(err, ErrSessionEnvelope) {
+		t.Fatalf("got %v with an unknown version, want ErrSessionEnvelope", err)
+	}
+	if _, err := ImportEncryptedKey(bytes.NewReader(envelope), make([]byte, 32)); err == nil {
+		t.Fatal("passphrase session imported with a key")
+	}
+
+	key := bytes.Repeat([]byte{7}, 32)
+	buf.Reset()
+	if err := ExportEncryptedKey(insta, &buf, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportEncryptedKey(&buf, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportEncryptedKey(insta, &buf, key[:16]); err == nil {
+		t.Fatal("short key accepted")
+	}
+}
+
+func TestExportFileMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	plain, encrypted := filepath.Join(dir, "session.json"), filepath.Join(dir, "session.enc")
+	// overwritten files become private too
+	if err := ioutil.WriteFile(plain, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Export(plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.ExportEncrypted(encrypted, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{plain, encrypted} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0600 {
+			t.Fatalf("%s has mode %v, want 0600", path, fi.Mode())
+		}
+	}
+	if _, err := ImportEncryptedFile(encrypted, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+}
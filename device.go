@@ -0,0 +1,77 @@
+package goinsta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Device describes the android device presented to instagram.
+//
+// A session should keep the same device for its whole life, so the device is
+// stored by Export and restored by Import.
+type Device struct {
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	// Codename is the device name, like "OnePlus3T".
+	Codename string `json:"codename"`
+	// CPU is the chipset vendor, like "qcom".
+	CPU string `json:"cpu"`
+	// AndroidVersion is the android API level, like 24.
+	AndroidVersion int `json:"android_version"`
+	// AndroidRelease is the android version, like "7.0".
+	AndroidRelease string `json:"android_release"`
+	DPI            int    `json:"dpi"`
+	// Resolution is the screen size, like "1080x1920".
+	Resolution string `json:"resolution"`
+}
+
+// DefaultDevice returns the device used by sessions without a device.
+func DefaultDevice() Device {
+	return Device{
+		Manufacturer:   "OnePlus",
+		Model:          "ONEPLUS A3010",
+		Codename:       "OnePlus3T",
+		CPU:            "qcom",
+		AndroidVersion: 24,
+		AndroidRelease: "7.0",
+		DPI:            380,
+		Resolution:     "1080x1920",
+	}
+}
+
+// UserAgent returns the User-Agent sent by the instagram app appVersion
+// running on d with locale, like "en-US".
+func (d Device) UserAgent(appVersion, locale string) string {
+	return fmt.Sprintf(
+		"Instagram %s Android (%d/%s; %ddpi; %s; %s; %s; %s; %s; %s)",
+		appVersion, d.AndroidVersion, d.AndroidRelease, d.DPI, d.Resolution,
+		d.Manufacturer, d.Model, d.Codename, d.CPU, strings.Replace(locale, "-", "_", -1),
+	)
+}
+
+// settings returns the device description sent in configure requests.
+func (d Device) settings() map[string]interface{} {
+	return map[string]interface{}{
+		"manufacturer":    d.Manufacturer,
+		"model":           d.Model,
+		"android_version": d.AndroidVersion,
+		"android_release": d.AndroidRelease,
+	}
+}
+
+// Device returns the device presented by the session.
+func (inst *Instagram) Device() Device {
+	return inst.device
+}
+
+func (inst *Instagram) userAgent() string {
+	return inst.device.UserAgent(inst.app.Version, inst.locale)
+}
+
+// WithDevice sets the device presented by the session.
+func WithDevice(d Device) Option {
+	return func(inst *Instagram) error {
+		inst.device = d
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package goinsta
+
+import (
+	"testing"
+)
+
+func TestDevice(t *testing.T) {
+	want := "Instagram 107.0.0.27.121 Android (24/7.0; 380dpi; 1080x1920; OnePlus; ONEPLUS A3010; OnePlus3T; qcom; en_US)"
+	if got := New("user", "pass").userAgent(); got != want {
+		t.Fatalf("got user agent %q, want %q", got, want)
+	}
+
+	device := Device{
+		Manufacturer:   "samsung",
+		Model:          "SM-G930F",
+		Codename:       "herolte",
+		CPU:            "samsungexynos8890",
+		AndroidVersion: 26,
+		AndroidRelease: "8.0.0",
+		DPI:            640,
+		Resolution:     "1440x2560",
+	}
+	insta, err := NewWithOptions("user", "pass", WithDevice(device), WithAppVersion(goInstaAppVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the device survives export and import
+	config := insta.config()
+	if config.Device == nil || *config.Device != device || config.App == nil || config.App.Version != goInstaAppVersion {
+		t.Fatalf("device not exported: %+v", config)
+	}
+	config.Cookies = nil
+	imported, err := ImportConfig(config, WithBaseURL("http://127.0.0.1:1/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.userAgent() != insta.userAgent() {
+		t.Fatalf("got user agent %q after import, want %q", imported.userAgent(), insta.userAgent())
+	}
+}
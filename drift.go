@@ -0,0 +1,357 @@
+package goinsta
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DriftKind is the kind of a SchemaDrift.
+type DriftKind int
+
+const (
+	// DriftUnknownField is a response field without a matching struct field.
+	DriftUnknownField DriftKind = iota
+	// DriftTypeMismatch is a response field whose JSON type cannot be decoded
+	// into its struct field.
+	DriftTypeMismatch
+)
+
+func (k DriftKind) String() string {
+	switch k {
+	case DriftUnknownField:
+		return "unknown field"
+	case DriftTypeMismatch:
+		return "type mismatch"
+	}
+	return "DriftKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// SchemaDrift is a difference between an instagram response and the
+// goinsta struct it is decoded into.
+type SchemaDrift struct {
+	Kind DriftKind
+	// Struct is the name of the Go struct holding the field, like "User" or "Item".
+	Struct string
+	// Field is the JSON name of the field.
+	Field string
+	// Path is the location of the field in the response, like "users[3].friendship_status.muting".
+	Path string
+	// JSONType is the type of the value in the response:
+	// "object", "array", "string", "number" or "bool".
+	JSONType string
+	// GoType is the type of the struct field, empty for unknown fields.
+	GoType string
+}
+
+func (d SchemaDrift) String() string {
+	s := d.Struct + "." + d.Field + ": " + d.Kind.String() + " at " + d.Path + " (" + d.JSONType
+	if d.GoType != "" {
+		s += " into " + d.GoType
+	}
+	return s + ")"
+}
+
+// DriftReporter receives the schema drifts found in responses.
+// Each drift is reported once per response, at its first location.
+//
+// It can be called from several goroutines at once.
+type DriftReporter func(d SchemaDrift)
+
+// SetDriftReporter enables the schema drift reporter of the session.
+// nil disables it.
+//
+// When enabled every response is decoded a second time into a generic
+// value and compared to the struct it fills, which makes decoding slower.
+// Use it to notice API changes, not on hot paths.
+func (inst *Instagram) SetDriftReporter(r DriftReporter) {
+	inst.mu.Lock()
+	inst.drift = r
+	inst.mu.Unlock()
+}
+
+// WithDriftReporter sets the schema drift reporter of the session. See SetDriftReporter.
+func WithDriftReporter(r DriftReporter) Option {
+	return func(inst *Instagram) error {
+		inst.SetDriftReporter(r)
+		return nil
+	}
+}
+
+// unmarshal decodes the response body into v, reporting the schema drifts
+// if a reporter is set.
+func (inst *Instagram) unmarshal(body []byte, v interface{}) error {
+	err := json.Unmarshal(body, v)
+	inst.reportDrift(body, v)
+	return err
+}
+
+// reportDrift reports the schema drifts between the response body and v,
+// the value it is decoded into.
+func (inst *Instagram) reportDrift(body []byte, v interface{}) {
+	inst.mu.RLock()
+	report := inst.drift
+	inst.mu.RUnlock()
+	if report == nil {
+		return
+	}
+	var generic interface{}
+	if json.Unmarshal(body, &generic) != nil {
+		return
+	}
+	for _, d := range findDrifts(generic, reflect.TypeOf(v)) {
+		report(d)
+	}
+}
+
+// findDrifts compares the decoded JSON value to the type t it is decoded into.
+func findDrifts(value interface{}, t reflect.Type) []SchemaDrift {
+	w := driftWalker{seen: make(map[driftKey]bool)}
+	w.walk("", "", value, t)
+	return w.drifts
+}
+
+type driftKey struct {
+	kind   DriftKind
+	typ    reflect.Type
+	field  string
+	goType reflect.Type
+}
+
+type driftWalker struct {
+	drifts []SchemaDrift
+	seen   map[driftKey]bool
+}
+
+func (w *driftWalker) add(d SchemaDrift, typ, goType reflect.Type) {
+	k := driftKey{d.Kind, typ, d.Field, goType}
+	if w.seen[k] {
+		return
+	}
+	w.seen[k] = true
+	w.drifts = append(w.drifts, d)
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	numberType          = reflect.TypeOf(json.Number(""))
+)
+
+// customDecoding reports whether t decodes itself.
+func customDecoding(t reflect.Type) bool {
+	return t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)
+}
+
+// walk compares value, found at path, to t. It returns false if value or
+// one of its elements cannot be decoded into t.
+//
+// name is the name given to t if it is an anonymous struct.
+func (w *driftWalker) walk(path, name string, value interface{}, t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if value == nil || t.Kind() == reflect.Interface || customDecoding(t) {
+		return true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			if t.Name() != "" {
+				name = t.Name()
+			}
+			w.walkStruct(path, name, v, t)
+			return true
+		case reflect.Map:
+			ok := true
+			for _, key := range sortedKeys(v) {
+				ok = w.walk(joinPath(path, key), name, v[key], t.Elem()) && ok
+			}
+			return ok
+		}
+	case []interface{}:
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			ok := true
+			for i, elem := range v {
+				ok = w.walk(path+"["+strconv.Itoa(i)+"]", name, elem, t.Elem()) && ok
+			}
+			return ok
+		}
+	case string:
+		switch t.Kind() {
+		case reflect.String:
+			return true
+		case reflect.Slice:
+			return t.Elem().Kind() == reflect.Uint8
+		}
+		return reflect.PtrTo(t).Implements(textUnmarshalerType)
+	case float64:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return t == numberType
+	case bool:
+		return t.Kind() == reflect.Bool
+	}
+	return false
+}
+
+// walkStruct compares the JSON object obj, found at path, to the struct t
+// named name.
+func (w *driftWalker) walkStruct(path, name string, obj map[string]interface{}, t reflect.Type) {
+	fields := structFields(t)
+	for _, key := range sortedKeys(obj) {
+		value := obj[key]
+		f, ok := fields.lookup(key)
+		if !ok {
+			w.add(SchemaDrift{
+				Kind:     DriftUnknownField,
+				Struct:   name,
+				Field:    key,
+				Path:     joinPath(path, key),
+				JSONType: jsonType(value),
+			}, t, nil)
+			continue
+		}
+		ft := f.typ
+		if f.quoted {
+			if _, ok := value.(string); ok {
+				continue
+			}
+		}
+		if !w.walk(joinPath(path, key), name+"."+f.name, value, ft) {
+			w.add(SchemaDrift{
+				Kind:     DriftTypeMismatch,
+				Struct:   name,
+				Field:    key,
+				Path:     joinPath(path, key),
+				JSONType: jsonType(value),
+				GoType:   ft.String(),
+			}, t, ft)
+		}
+	}
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return "null"
+}
+
+// jsonField is a struct field decoded by encoding/json.
+type jsonField struct {
+	// name is the Go name of the field.
+	name string
+	typ  reflect.Type
+	// quoted fields have the ",string" option.
+	quoted bool
+}
+
+// jsonFields are the fields of a struct by JSON name.
+type jsonFields map[string]jsonField
+
+// lookup finds the field of key like encoding/json, preferring an exact
+// match to a case-insensitive one.
+func (fields jsonFields) lookup(key string) (jsonField, bool) {
+	if f, ok := fields[key]; ok {
+		return f, true
+	}
+	for name, f := range fields {
+		if strings.EqualFold(name, key) {
+			return f, true
+		}
+	}
+	return jsonField{}, false
+}
+
+var fieldCache sync.Map // reflect.Type -> jsonFields
+
+// structFields returns the fields of the struct t decoded by encoding/json,
+// including the promoted fields of embedded structs.
+func structFields(t reflect.Type) jsonFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(jsonFields)
+	}
+	fields := make(jsonFields)
+	addStructFields(fields, t, make(map[reflect.Type]bool))
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+func addStructFields(fields jsonFields, t reflect.Type, visited map[reflect.Type]bool) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, opts = tag[:i], tag[i:]
+		}
+		ft := sf.Type
+		if sf.Anonymous && name == "" {
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = jsonField{name: sf.Name, typ: ft, quoted: strings.Contains(opts, ",string")}
+		}
+	}
+	// fields of the outer struct shadow the promoted ones
+	for _, et := range embedded {
+		addStructFields(fields, et, visited)
+	}
+}
@@ -0,0 +1,96 @@
+package goinsta
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDriftReporter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok","users":[
+			{"pk":1,"username":"alice","is_private":"no","new_badge":{}},
+			{"pk":2,"username":"bob","is_private":"yes","new_badge":{}},
+			{"pk":3,"username":42}
+		],"big_list":false,"next_max_id":null}`))
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var drifts []SchemaDrift
+	insta, err := NewWithOptions("user", "pass",
+		WithBaseURL(srv.URL),
+		WithDriftReporter(func(d SchemaDrift) {
+			mu.Lock()
+			drifts = append(drifts, d)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	insta.Account = &Account{ID: 1, inst: insta}
+	users := insta.Account.Followers()
+	if users.Next() {
+		t.Fatal("response with mismatched types decoded")
+	}
+
+	want := []SchemaDrift{
+		{Kind: DriftTypeMismatch, Struct: "User", Field: "is_private", Path: "users[0].is_private", JSONType: "string", GoType: "bool"},
+		{Kind: DriftUnknownField, Struct: "User", Field: "new_badge", Path: "users[0].new_badge", JSONType: "object"},
+		{Kind: DriftTypeMismatch, Struct: "User", Field: "username", Path: "users[2].username", JSONType: "number", GoType: "string"},
+	}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Fatalf("got drifts\n%v\nwant\n%v", drifts, want)
+	}
+
+	drifts = nil
+	insta.SetDriftReporter(nil)
+	users = insta.Account.Followers()
+	users.Next()
+	if len(drifts) != 0 {
+		t.Fatalf("got %d drifts after disabling the reporter", len(drifts))
+	}
+}
+
+func TestFindDrifts(t *testing.T) {
+	type inner struct {
+		N json.Number `json:"n"`
+	}
+	var v struct {
+		Count  int64             `json:"count,string"`
+		Tags   []string          `json:"tags"`
+		Extra  map[string]inner  `json:"extra"`
+		Any    interface{}       `json:"any"`
+		Nested struct{ A bool }  `json:"nested"`
+		Ignore string            `json:"-"`
+		Labels map[string]string `json:"labels"`
+	}
+	generic := map[string]interface{}{
+		"count":  "12",
+		"tags":   []interface{}{"a", 1.0},
+		"extra":  map[string]interface{}{"x": map[string]interface{}{"n": 1.0, "m": true}},
+		"any":    []interface{}{1.0},
+		"nested": map[string]interface{}{"a": true, "b": "c"},
+		"-":      "x",
+		"labels": nil,
+	}
+	got := findDrifts(generic, reflect.TypeOf(&v))
+	var fields []string
+	for _, d := range got {
+		fields = append(fields, d.Kind.String()+" "+d.Struct+"."+d.Field)
+	}
+	want := []string{
+		"unknown field .-",
+		"unknown field inner.m",
+		"unknown field .Nested.b",
+		"type mismatch .tags",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("got %q, want %q", fields, want)
+	}
+}
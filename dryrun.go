@@ -0,0 +1,102 @@
+package goinsta
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Action is a mutating call recorded instead of being sent in dry-run mode.
+type Action struct {
+	Time     time.Time
+	Method   string
+	Endpoint string
+	// Query are the parameters that would have been sent.
+	Query map[string]string
+	// Data is the signed data of the request, if any.
+	Data map[string]interface{}
+}
+
+// dryRunResponse answers the recorded actions.
+const dryRunResponse = `{"status":"ok"}`
+
+// SetDryRun enables or disables dry-run mode.
+//
+// In dry-run mode reads and login requests are sent as usual, while calls
+// changing anything (like, follow, comment, delete, upload, direct messages...)
+// are recorded as Actions and answered with a successful empty response.
+func (inst *Instagram) SetDryRun(enabled bool) {
+	inst.mu.Lock()
+	inst.dryRun = enabled
+	inst.mu.Unlock()
+}
+
+// DryRun reports whether dry-run mode is enabled.
+func (inst *Instagram) DryRun() bool {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.dryRun
+}
+
+// WithDryRun enables dry-run mode. See SetDryRun.
+func WithDryRun() Option {
+	return func(inst *Instagram) error {
+		inst.SetDryRun(true)
+		return nil
+	}
+}
+
+// Actions returns the actions recorded in dry-run mode, oldest first.
+func (inst *Instagram) Actions() []Action {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return append([]Action(nil), inst.actions...)
+}
+
+// ResetActions forgets the recorded actions.
+func (inst *Instagram) ResetActions() {
+	inst.mu.Lock()
+	inst.actions = nil
+	inst.mu.Unlock()
+}
+
+// record stores the action of a call when dry-run mode is enabled and call
+// changes something. It reports whether the call must not be sent.
+func (inst *Instagram) record(method, endpoint string, query map[string]string) bool {
+	if !inst.DryRun() {
+		return false
+	}
+
+	a := Action{
+		Time:     time.Now(),
+		Method:   method,
+		Endpoint: endpoint,
+		Query:    make(map[string]string, len(query)),
+	}
+	for k, v := range query {
+		a.Query[k] = v
+	}
+	if body, ok := query["signed_body"]; ok {
+		a.Data = signedData(body)
+	}
+	inst.mu.Lock()
+	inst.actions = append(inst.actions, a)
+	inst.mu.Unlock()
+	inst.logger().Info("dry-run", "method", method, "endpoint", endpoint)
+	return true
+}
+
+// mutates reports whether o changes something.
+func (o *reqOptions) mutates() bool {
+	return o.IsPost && !o.Handshake && !o.Idempotent
+}
+
+// signedData returns the data of a signed body, or nil.
+func signedData(body string) map[string]interface{} {
+	i := strings.IndexByte(body, '.')
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(body[i+1:]), &data); err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,103 @@
+package goinsta
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// reads are sent
+	if _, err = insta.sendRequest(ctx, &reqOptions{Endpoint: urlTimeline}); err != nil {
+		t.Fatal(err)
+	}
+
+	item := &Item{ID: "1_2", media: &FeedMedia{inst: insta}}
+	if err = item.LikeContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var photo bytes.Buffer
+	png.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 4, 3)))
+	if _, err = insta.UploadPhotoContext(ctx, &photo, "caption", 87, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != 1 || paths[0] != "/api/v1/"+urlTimeline {
+		t.Fatalf("sent requests %v, want only the timeline", paths)
+	}
+	actions := insta.Actions()
+	if len(actions) != 3 {
+		t.Fatalf("got %d actions, want 3: %+v", len(actions), actions)
+	}
+	if actions[0].Endpoint != "media/1_2/like/" || actions[0].Data["media_id"] != "1_2" {
+		t.Errorf("unexpected like action %+v", actions[0])
+	}
+	if actions[1].Endpoint != urlUploadPhoto || actions[2].Data["caption"] != "caption" {
+		t.Errorf("unexpected upload actions %+v", actions[1:])
+	}
+
+	insta.ResetActions()
+	insta.SetDryRun(false)
+	item.LikeContext(ctx)
+	if len(paths) != 2 || len(insta.Actions()) != 0 {
+		t.Fatalf("like not sent after disabling dry-run: %v", paths)
+	}
+}
+
+func TestDryRunContacts(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	contacts := []Contact{{Numbers: []string{"+33600000000"}, Name: "Bob"}}
+	if _, err = insta.Contacts.SyncContactsContext(ctx, &contacts); err != nil {
+		t.Fatal(err)
+	}
+	if err = insta.Contacts.UnlinkContactsContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// the requests of the login flow are sent
+	if err = insta.expose(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != 1 || paths[0] != "/api/v1/"+urlExpose {
+		t.Fatalf("sent requests %v, want only the login flow", paths)
+	}
+	var endpoints []string
+	for _, a := range insta.Actions() {
+		endpoints = append(endpoints, a.Endpoint)
+	}
+	want := []string{"address_book/acquire_owner_contacts/", "address_book/link/", "address_book/unlink/"}
+	if fmt.Sprint(endpoints) != fmt.Sprint(want) {
+		t.Fatalf("got actions %v, want %v", endpoints, want)
+	}
+}
@@ -0,0 +1,95 @@
+package goinsta
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is sent by every request. Responses are decoded by readBody.
+const acceptEncoding = "gzip, deflate"
+
+// SetRequestCompression enables gzip compression of POST form bodies
+// of at least minSize bytes. Zero disables compression, which is the default.
+func (inst *Instagram) SetRequestCompression(minSize int) {
+	inst.mu.Lock()
+	inst.compressMin = minSize
+	inst.mu.Unlock()
+}
+
+// WithRequestCompression enables compression of large form bodies.
+// See SetRequestCompression.
+func WithRequestCompression(minSize int) Option {
+	return func(inst *Instagram) error {
+		inst.SetRequestCompression(minSize)
+		return nil
+	}
+}
+
+// compress returns payload gzipped, with its content encoding, if
+// compression is enabled for its size.
+func (inst *Instagram) compress(payload []byte) ([]byte, string) {
+	inst.mu.RLock()
+	min := inst.compressMin
+	inst.mu.RUnlock()
+	if min <= 0 || len(payload) < min {
+		return payload, ""
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(payload); err != nil {
+		return payload, ""
+	}
+	if err := w.Close(); err != nil {
+		return payload, ""
+	}
+	return buf.Bytes(), "gzip"
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// readBody reads the body of resp decoded according to its Content-Encoding.
+// It returns the number of bytes received, before decoding.
+func readBody(resp *http.Response) ([]byte, int64, error) {
+	cr := &countingReader{r: resp.Body}
+	r, err := decodeBody(cr, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, cr.n, err
+	}
+	body, err := ioutil.ReadAll(r)
+	return body, cr.n, err
+}
+
+// decodeBody returns a reader decoding r encoded with encoding.
+func decodeBody(r io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	case "deflate":
+		// deflate should be zlib wrapped, but raw deflate is common too
+		br := bufio.NewReader(r)
+		h, err := br.Peek(2)
+		if err == nil && h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	}
+	return r, nil
+}
@@ -0,0 +1,87 @@
+package goinsta
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentEncoding(t *testing.T) {
+	const resp = `{"status":"ok"}`
+	var form string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			body, _ = gzip.NewReader(r.Body)
+		}
+		b, _ := ioutil.ReadAll(body)
+		form = string(b)
+
+		var zw io.WriteCloser
+		encoding := r.URL.Query().Get("encoding")
+		switch encoding {
+		case "gzip":
+			zw = gzip.NewWriter(w)
+		case "zlib":
+			encoding = "deflate"
+			zw = zlib.NewWriter(w)
+		case "flate":
+			encoding = "deflate"
+			zw, _ = flate.NewWriter(w, flate.DefaultCompression)
+		default:
+			w.Write([]byte(resp))
+			return
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		zw.Write([]byte(resp))
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithRequestCompression(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, encoding := range []string{"", "gzip", "zlib", "flate"} {
+		body, err := insta.sendRequest(context.Background(), &reqOptions{
+			Endpoint: urlTimeline + "?encoding=" + encoding,
+		})
+		if err != nil || string(body) != resp {
+			t.Errorf("%q: got %s, %v", encoding, body, err)
+		}
+	}
+
+	// large bodies are compressed
+	long := strings.Repeat("a", 100)
+	_, err = insta.sendRequest(context.Background(), &reqOptions{
+		Endpoint: urlTimeline,
+		IsPost:   true,
+		Query:    map[string]string{"q": long},
+	})
+	if err != nil || form != "q="+long {
+		t.Fatalf("got form %q, %v", form, err)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	insta := New("user", "pass")
+	payload := bytes.Repeat([]byte("a"), 1000)
+	if _, encoding := insta.compress(payload); encoding != "" {
+		t.Fatal("compressed while disabled")
+	}
+	insta.SetRequestCompression(100)
+	if b, encoding := insta.compress(payload); encoding != "gzip" || len(b) >= len(payload) {
+		t.Fatalf("got %d bytes with encoding %q", len(b), encoding)
+	}
+	if _, encoding := insta.compress(payload[:10]); encoding != "" {
+		t.Fatal("small payload compressed")
+	}
+}
@@ -0,0 +1,201 @@
+package goinsta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of errors returned by instagram.
+//
+// Errors returned by API calls can be compared with errors.Is:
+//
+//	if errors.Is(err, goinsta.ErrFeedbackRequired) {
+//		// slow down
+//	}
+var (
+	// ErrLoginRequired means the session is not logged in anymore.
+	ErrLoginRequired = errors.New("login required")
+	// ErrChallengeRequired means instagram wants the user to solve a challenge.
+	// See Challenge.Solve.
+	ErrChallengeRequired = errors.New("challenge required")
+	// ErrTwoFactorRequired means the login needs the two factor verification code.
+	// See Instagram.TwoFactorLogin.
+	ErrTwoFactorRequired = errors.New("two factor authentication required")
+	// ErrCheckpointRequired means the account is locked behind a checkpoint.
+	ErrCheckpointRequired = errors.New("checkpoint required")
+	// ErrFeedbackRequired means instagram blocked the action, usually because of spam.
+	ErrFeedbackRequired = errors.New("feedback required")
+	// ErrRateLimited means too many requests have been sent.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotFound means the requested object does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrPrivateAccount means the requested user is private and not followed by the session.
+	ErrPrivateAccount = errors.New("private account")
+	// ErrServerError means instagram failed to process the request (HTTP 5xx).
+	ErrServerError = errors.New("server error")
+	// ErrNetwork means the request failed before a response was read,
+	// e.g. the connection was reset.
+	ErrNetwork = errors.New("network error")
+)
+
+// APIError is the error returned when instagram answers a request with an error.
+//
+// Use errors.Is with the Err* variables to know the kind of the error and
+// errors.As to get the decoded response (ChallengeError, TwoFactorError,
+// Error400, ErrorN or Error503).
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// ErrorType is the error_type field of the response.
+	ErrorType string
+	// Message is the message field of the response.
+	Message string
+	// Endpoint is the endpoint of the failed request.
+	Endpoint string
+	// Body is the raw response body.
+	Body []byte
+	// RetryAfter is the wait requested by the Retry-After header, if any.
+	RetryAfter time.Duration
+
+	kind error
+	err  error
+}
+
+func (e *APIError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %d %s", e.Endpoint, e.StatusCode, msg)
+}
+
+// Unwrap returns the decoded instagram error.
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error is of kind target.
+func (e *APIError) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
+// Kind returns the Err* variable matching the error or nil if it is unknown.
+func (e *APIError) Kind() error {
+	return e.kind
+}
+
+func errorKind(code int, errorType, message string) error {
+	switch {
+	case message == "login_required":
+		return ErrLoginRequired
+	case message == "challenge_required":
+		return ErrChallengeRequired
+	case errorType == "two_factor_required":
+		return ErrTwoFactorRequired
+	case message == "checkpoint_required",
+		errorType == "checkpoint_challenge_required",
+		errorType == "checkpoint_logged_out":
+		return ErrCheckpointRequired
+	case message == "feedback_required":
+		return ErrFeedbackRequired
+	case code == http.StatusTooManyRequests,
+		errorType == "rate_limit_error",
+		strings.Contains(message, "wait a few minutes"):
+		return ErrRateLimited
+	case message == "Not authorized to view user":
+		return ErrPrivateAccount
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code >= 500:
+		return ErrServerError
+	}
+	return nil
+}
+
+func isError(endpoint string, code int, body []byte) error {
+	if code == 200 {
+		return nil
+	}
+	apiErr := &APIError{
+		StatusCode: code,
+		Endpoint:   endpoint,
+		Body:       body,
+	}
+
+	var resp struct {
+		Message   string `json:"message"`
+		ErrorType string `json:"error_type"`
+	}
+	if json.Unmarshal(body, &resp) == nil {
+		apiErr.Message = resp.Message
+		apiErr.ErrorType = resp.ErrorType
+
+		switch code {
+		case 503:
+			apiErr.err = Error503{
+				Message: "Instagram API error. Try it later.",
+			}
+		case 400:
+			if resp.ErrorType == "two_factor_required" {
+				ierr := TwoFactorError{}
+				json.Unmarshal(body, &ierr)
+				apiErr.err = ierr
+				break
+			}
+			ierr := Error400{}
+			json.Unmarshal(body, &ierr)
+			if ierr.Message == "challenge_required" {
+				apiErr.err = ierr.ChallengeError
+			} else if ierr.Message != "" {
+				apiErr.err = ierr
+			}
+		default:
+			ierr := ErrorN{}
+			json.Unmarshal(body, &ierr)
+			apiErr.err = ierr
+		}
+	}
+	apiErr.kind = errorKind(code, apiErr.ErrorType, apiErr.Message)
+	return apiErr
+}
+
+// networkError wraps errors happened while sending a request or reading its response.
+type networkError struct {
+	err error
+}
+
+func (e networkError) Error() string {
+	return e.err.Error()
+}
+
+func (e networkError) Unwrap() error {
+	return e.err
+}
+
+func (e networkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
+// parseRetryAfter parses Retry-After header values (seconds or HTTP date).
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package goinsta
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsError(t *testing.T) {
+	tests := []struct {
+		code int
+		body string
+		kind error
+	}{
+		{403, `{"message":"login_required","status":"fail"}`, ErrLoginRequired},
+		{400, `{"message":"challenge_required","challenge":{"api_path":"/challenge/1/abc/"},"status":"fail","error_type":"checkpoint_challenge_required"}`, ErrChallengeRequired},
+		{400, `{"message":"checkpoint_required","status":"fail"}`, ErrCheckpointRequired},
+		{400, `{"message":"feedback_required","spam":true,"status":"fail"}`, ErrFeedbackRequired},
+		{429, `{"message":"Please wait a few minutes before you try again.","status":"fail"}`, ErrRateLimited},
+		{404, `<html>Not Found</html>`, ErrNotFound},
+		{400, `{"message":"Not authorized to view user","status":"fail"}`, ErrPrivateAccount},
+		{502, ``, ErrServerError},
+	}
+	for _, test := range tests {
+		err := isError("users/1/info/", test.code, []byte(test.body))
+		if !errors.Is(err, test.kind) {
+			t.Errorf("%d %s: got %v, want %v", test.code, test.body, err, test.kind)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%v is not an *APIError", err)
+		}
+		if apiErr.StatusCode != test.code || apiErr.Endpoint != "users/1/info/" || string(apiErr.Body) != test.body {
+			t.Errorf("unexpected error fields: %+v", apiErr)
+		}
+	}
+
+	err := isError("accounts/login/", 400, []byte(tests[1].body))
+	var challenge ChallengeError
+	if !errors.As(err, &challenge) || challenge.Challenge.APIPath != "/challenge/1/abc/" {
+		t.Errorf("cannot get ChallengeError from %v", err)
+	}
+	if err := isError("feed/timeline/", 200, []byte(`{"status":"ok"}`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
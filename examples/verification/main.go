@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"os"
 
@@ -14,8 +15,8 @@ func main() {
 		os.Getenv("INSTAGRAM_PASSWORD"),
 	)
 	if err := insta.Login(); err != nil {
-		switch v := err.(type) {
-		case goinsta.ChallengeError:
+		var v goinsta.ChallengeError
+		if errors.As(err, &v) {
 			err := insta.Challenge.Process(v.Challenge.APIPath)
 			if err != nil {
 				log.Fatalln(err)
@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -19,12 +19,17 @@ func newFeed(inst *Instagram) *Feed {
 
 // Feed search by locationID
 func (feed *Feed) LocationID(locationID int64) (*FeedLocation, error) {
+	return feed.LocationIDContext(context.Background(), locationID)
+}
+
+// LocationIDContext is the context-aware version of LocationID.
+func (feed *Feed) LocationIDContext(ctx context.Context, locationID int64) (*FeedLocation, error) {
 	insta := feed.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlFeedLocationID, locationID),
 			Query: map[string]string{
-				"rank_token":     insta.rankToken,
+				"rank_token":     insta.rankToken.Get(),
 				"ranked_content": "true",
 			},
 		},
@@ -34,7 +39,7 @@ func (feed *Feed) LocationID(locationID int64) (*FeedLocation, error) {
 	}
 
 	res := &FeedLocation{}
-	err = json.Unmarshal(body, res)
+	err = insta.unmarshal(body, res)
 	return res, err
 }
 
@@ -55,12 +60,17 @@ type FeedLocation struct {
 //
 // (sorry for returning FeedTag. See #FeedTag)
 func (feed *Feed) Tags(tag string) (*FeedTag, error) {
+	return feed.TagsContext(context.Background(), tag)
+}
+
+// TagsContext is the context-aware version of Tags.
+func (feed *Feed) TagsContext(ctx context.Context, tag string) (*FeedTag, error) {
 	insta := feed.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlFeedTag, tag),
 			Query: map[string]string{
-				"rank_token":     insta.rankToken,
+				"rank_token":     insta.rankToken.Get(),
 				"ranked_content": "true",
 			},
 		},
@@ -69,7 +79,7 @@ func (feed *Feed) Tags(tag string) (*FeedTag, error) {
 		return nil, err
 	}
 	res := &FeedTag{}
-	err = json.Unmarshal(body, res)
+	err = insta.unmarshal(body, res)
 	if err != nil {
 		return nil, err
 	}
@@ -115,24 +125,29 @@ func (ft *FeedTag) setValues() {
 
 // Next paginates over hashtag feed.
 func (ft *FeedTag) Next() bool {
+	return ft.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (ft *FeedTag) NextContext(ctx context.Context) bool {
 	if ft.err != nil {
 		return false
 	}
 
 	insta := ft.inst
 	name := ft.name
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Query: map[string]string{
 				"max_id":     ft.NextID,
-				"rank_token": insta.rankToken,
+				"rank_token": insta.rankToken.Get(),
 			},
 			Endpoint: fmt.Sprintf(urlFeedTag, name),
 		},
 	)
 	if err == nil {
 		newFT := &FeedTag{}
-		err = json.Unmarshal(body, newFT)
+		err = insta.unmarshal(body, newFT)
 		if err == nil {
 			*ft = *newFT
 			ft.inst = insta
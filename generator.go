@@ -52,11 +52,11 @@ func generateUUID() string {
 	return uuid
 }
 
-func generateSignature(data string) map[string]string {
+func (inst *Instagram) generateSignature(data string) map[string]string {
 	m := make(map[string]string)
-	m["ig_sig_key_version"] = goInstaSigKeyVersion
+	m["ig_sig_key_version"] = inst.app.SigKeyVersion
 	m["signed_body"] = fmt.Sprintf(
-		"%s.%s", generateHMAC(data, goInstaIGSigKey), data,
+		"%s.%s", generateHMAC(data, inst.app.SigKey), data,
 	)
 	return m
 }
@@ -1,8 +1,10 @@
 package goinsta
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -30,23 +33,66 @@ import (
 //
 // Also you can use SetProxy and UnsetProxy to set and unset proxy.
 // Golang also provides the option to set a proxy using HTTP_PROXY env var.
+//
+// Instagram methods are safe for concurrent use, but Login and the Import
+// functions must return before the session is shared, and the exported
+// fields must not be replaced. Values returned by the session, such as the
+// iterators Users, FeedMedia, StoryMedia, Conversation or Comments, and
+// the Challenge must be used by one goroutine at a time.
 type Instagram struct {
 	user string
-	pass string
+	pass syncString
 	// device id: android-1923fjnma8123
-	dID string
+	dID syncString
 	// uuid: 8493-1233-4312312-5123
-	uuid string
+	uuid syncString
 	// rankToken
-	rankToken string
+	rankToken syncString
 	// token
-	token string
+	token syncString
 	// phone id
-	pid string
+	pid syncString
 	// ads id
-	adid string
+	adid syncString
 	// challenge URL
-	challengeURL string
+	challengeURL syncString
+	// locale sent in Accept-Language
+	locale string
+	// device presented to instagram
+	device Device
+	// instagram app presented to instagram
+	app AppProfile
+
+	// mu guards the fields below and Account
+	mu sync.RWMutex
+	// hosts are the base URLs of the API
+	hosts Hosts
+	// retry is the policy used to send failed requests again
+	retry RetryPolicy
+	// limiter paces requests
+	limiter Limiter
+	// interceptors wrap every API call
+	interceptors []Interceptor
+	// log receives the session log entries
+	log Logger
+	// metrics receives request statistics
+	metrics Metrics
+	// compressMin is the minimum size of compressed request bodies
+	compressMin int
+	// dryRun records mutating calls in actions instead of sending them
+	dryRun  bool
+	actions []Action
+	// drift reports the differences between responses and structs
+	drift DriftReporter
+	// store persists the session
+	store SessionStore
+	// proxy is the proxy set by SetProxy
+	proxy *ProxyConfig
+	// created and refreshed are the times of the login and of the
+	// last cookie update
+	created, refreshed time.Time
+	// twoFactor is the login waiting for its verification code
+	twoFactor *TwoFactorInfo
 
 	// Instagram objects
 
@@ -77,57 +123,170 @@ type Instagram struct {
 // SetHTTPClient sets http client.  This further allows users to use this functionality
 // for HTTP testing using a mocking HTTP client Transport, which avoids direct calls to
 // the Instagram, instead of returning mocked responses.
+//
+// client must not be modified afterwards.
 func (inst *Instagram) SetHTTPClient(client *http.Client) {
+	inst.mu.Lock()
 	inst.c = client
+	inst.mu.Unlock()
 }
 
 // SetHTTPTransport sets http transport. This further allows users to tweak the underlying
 // low level transport for adding additional fucntionalities.
 func (inst *Instagram) SetHTTPTransport(transport http.RoundTripper) {
-	inst.c.Transport = transport
+	inst.updateClient(func(c *http.Client) {
+		c.Transport = transport
+	})
+}
+
+// client returns the http client of the session.
+func (inst *Instagram) client() *http.Client {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.c
+}
+
+// updateClient replaces the http client by a copy modified by fn,
+// so that requests in flight keep using the previous one.
+func (inst *Instagram) updateClient(fn func(c *http.Client)) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	c := *inst.c
+	fn(&c)
+	inst.c = &c
+}
+
+// SetHosts overrides the base URLs used to reach instagram.
+//
+// Empty fields are set to the instagram defaults. Cookies stored for the
+// previous API host are not moved to the new one.
+func (inst *Instagram) SetHosts(hosts Hosts) {
+	inst.mu.Lock()
+	inst.hosts = hosts.withDefaults()
+	inst.mu.Unlock()
+}
+
+// SetBaseURL points every host below base. See HostsFromBaseURL.
+func (inst *Instagram) SetBaseURL(base string) error {
+	hosts, err := HostsFromBaseURL(base)
+	if err == nil {
+		inst.SetHosts(hosts)
+	}
+	return err
+}
+
+// Hosts returns the base URLs used to reach instagram.
+func (inst *Instagram) Hosts() Hosts {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.hosts
 }
 
 // SetDeviceID sets device id
 func (inst *Instagram) SetDeviceID(id string) {
-	inst.dID = id
+	inst.dID.Set(id)
 }
 
 // SetUUID sets uuid
 func (inst *Instagram) SetUUID(uuid string) {
-	inst.uuid = uuid
+	inst.uuid.Set(uuid)
 }
 
 // SetPhoneID sets phone id
 func (inst *Instagram) SetPhoneID(id string) {
-	inst.pid = id
+	inst.pid.Set(id)
 }
 
 // SetCookieJar sets the Cookie Jar. This further allows to use a custom implementation
 // of a cookie jar which may be backed by a different data store such as redis.
 func (inst *Instagram) SetCookieJar(jar http.CookieJar) error {
-	url, err := neturl.Parse(goInstaAPIUrl)
+	url, err := inst.Hosts().apiURL()
 	if err != nil {
 		return err
 	}
 	// First grab the cookies from the existing jar and we'll put it in the new jar.
-	cookies := inst.c.Jar.Cookies(url)
-	inst.c.Jar = jar
-	inst.c.Jar.SetCookies(url, cookies)
+	cookies := inst.client().Jar.Cookies(url)
+	jar.SetCookies(url, cookies)
+	inst.updateClient(func(c *http.Client) {
+		c.Jar = jar
+	})
 	return nil
 }
 
+// account returns the logged in account, or nil.
+func (inst *Instagram) account() *Account {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.Account
+}
+
+// accountID returns the id of the session account, 0 before login.
+func (inst *Instagram) accountID() int64 {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.Account == nil {
+		return 0
+	}
+	return inst.Account.ID
+}
+
+// updateAccount updates account in place with the information of updated.
+// Accounts are written under mu since requests read the session account
+// concurrently, see accountID.
+func (inst *Instagram) updateAccount(account, updated *Account) {
+	updated.inst = inst
+	inst.mu.Lock()
+	*account = *updated
+	inst.mu.Unlock()
+}
+
+// setAccount adopts the account of a new login.
+func (inst *Instagram) setAccount(account *Account) {
+	account.inst = inst
+	inst.mu.Lock()
+	inst.Account = account
+	inst.twoFactor = nil
+	inst.mu.Unlock()
+	inst.rankToken.Set(strconv.FormatInt(account.ID, 10) + "_" + inst.uuid.Get())
+	inst.sessionRefreshed(true)
+}
+
+// sessionRefreshed records that the session cookies were updated,
+// by a login if login is true.
+func (inst *Instagram) sessionRefreshed(login bool) {
+	now := time.Now()
+	inst.mu.Lock()
+	if login {
+		inst.created = now
+	}
+	inst.refreshed = now
+	inst.mu.Unlock()
+}
+
+// CreatedAt returns the time the session logged in, zero if unknown.
+func (inst *Instagram) CreatedAt() time.Time {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.created
+}
+
+// RefreshedAt returns the last time instagram updated the session cookies.
+func (inst *Instagram) RefreshedAt() time.Time {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.refreshed
+}
+
 // New creates Instagram structure
 func New(username, password string) *Instagram {
 	// this call never returns error
 	jar, _ := cookiejar.New(nil)
 	inst := &Instagram{
-		user: username,
-		pass: password,
-		dID: generateDeviceID(
-			generateMD5Hash(username + password),
-		),
-		uuid: generateUUID(), // both uuid must be differents
-		pid:  generateUUID(),
+		user:   username,
+		locale: defaultLocale,
+		device: DefaultDevice(),
+		app:    DefaultAppProfile(),
+		hosts:  DefaultHosts(),
 		c: &http.Client{
 			Transport: &http.Transport{
 				Proxy: http.ProxyFromEnvironment,
@@ -135,6 +294,13 @@ func New(username, password string) *Instagram {
 			Jar: jar,
 		},
 	}
+	inst.pass.Set(password)
+	inst.dID.Set(generateDeviceID(
+		generateMD5Hash(username + password),
+	))
+	inst.uuid.Set(generateUUID()) // both uuid must be differents
+	inst.pid.Set(generateUUID())
+	inst.adid.Set(generateUUID())
 	inst.init()
 
 	return inst
@@ -153,26 +319,42 @@ func (inst *Instagram) init() {
 }
 
 // SetProxy sets proxy for connection.
+//
+// The proxy is exported with the session, including the credentials of url.
 func (inst *Instagram) SetProxy(url string, insecure bool) error {
 	uri, err := neturl.Parse(url)
 	if err == nil {
-		inst.c.Transport = &http.Transport{
-			Proxy: http.ProxyURL(uri),
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: insecure,
-			},
-		}
+		inst.updateClient(func(c *http.Client) {
+			c.Transport = &http.Transport{
+				Proxy: http.ProxyURL(uri),
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: insecure,
+				},
+			}
+		})
+		inst.mu.Lock()
+		inst.proxy = &ProxyConfig{URL: url, Insecure: insecure}
+		inst.mu.Unlock()
 	}
 	return err
 }
 
 // UnsetProxy unsets proxy for connection.
 func (inst *Instagram) UnsetProxy() {
-	inst.c.Transport = nil
+	inst.updateClient(func(c *http.Client) {
+		c.Transport = nil
+	})
+	inst.mu.Lock()
+	inst.proxy = nil
+	inst.mu.Unlock()
 }
 
-// Save exports config to ~/.goinsta
+// Save saves the session to its SessionStore or, if it has none,
+// exports it to ~/.goinsta.
 func (inst *Instagram) Save() error {
+	if store := inst.sessionStore(); store != nil {
+		return inst.saveSession(store)
+	}
 	home := os.Getenv("HOME")
 	if home == "" {
 		home = os.Getenv("home") // for plan9
@@ -180,49 +362,53 @@ func (inst *Instagram) Save() error {
 	return inst.Export(filepath.Join(home, ".goinsta"))
 }
 
-// Export exports *Instagram object options
+// Export exports *Instagram object options to the file path,
+// only readable by its owner. See ExportEncrypted to protect the session.
 func (inst *Instagram) Export(path string) error {
-	url, err := neturl.Parse(goInstaAPIUrl)
+	bytes, err := json.Marshal(inst.config())
 	if err != nil {
 		return err
 	}
 
+	return writeSessionFile(path, bytes)
+}
+
+// config returns the exported state of the session.
+func (inst *Instagram) config() ConfigFile {
+	hosts := inst.Hosts()
+	device, app := inst.device, inst.app
 	config := ConfigFile{
-		ID:        inst.Account.ID,
-		User:      inst.user,
-		DeviceID:  inst.dID,
-		UUID:      inst.uuid,
-		RankToken: inst.rankToken,
-		Token:     inst.token,
-		PhoneID:   inst.pid,
-		Cookies:   inst.c.Jar.Cookies(url),
-	}
-	bytes, err := json.Marshal(config)
-	if err != nil {
-		return err
+		Version:      ConfigVersion,
+		User:         inst.user,
+		DeviceID:     inst.dID.Get(),
+		UUID:         inst.uuid.Get(),
+		RankToken:    inst.rankToken.Get(),
+		Token:        inst.token.Get(),
+		PhoneID:      inst.pid.Get(),
+		Hosts:        hosts,
+		Device:       &device,
+		App:          &app,
+		AdID:         inst.adid.Get(),
+		Locale:       inst.locale,
+		ChallengeURL: inst.challengeURL.Get(),
 	}
-
-	return ioutil.WriteFile(path, bytes, 0644)
+	inst.mu.RLock()
+	if inst.proxy != nil {
+		proxy := *inst.proxy
+		config.Proxy = &proxy
+	}
+	config.CreatedAt, config.RefreshedAt = inst.created, inst.refreshed
+	inst.mu.RUnlock()
+	config.ID = inst.accountID()
+	if url, err := hosts.apiURL(); err == nil {
+		config.Cookies = inst.client().Jar.Cookies(url)
+	}
+	return config
 }
 
 // Export exports selected *Instagram object options to an io.Writer
 func Export(inst *Instagram, writer io.Writer) error {
-	url, err := neturl.Parse(goInstaAPIUrl)
-	if err != nil {
-		return err
-	}
-
-	config := ConfigFile{
-		ID:        inst.Account.ID,
-		User:      inst.user,
-		DeviceID:  inst.dID,
-		UUID:      inst.uuid,
-		RankToken: inst.rankToken,
-		Token:     inst.token,
-		PhoneID:   inst.pid,
-		Cookies:   inst.c.Jar.Cookies(url),
-	}
-	bytes, err := json.Marshal(config)
+	bytes, err := json.Marshal(inst.config())
 	if err != nil {
 		return err
 	}
@@ -231,9 +417,7 @@ func Export(inst *Instagram, writer io.Writer) error {
 }
 
 // ImportReader imports instagram configuration from io.Reader
-//
-// This function does not set proxy automatically. Use SetProxy after this call.
-func ImportReader(r io.Reader) (*Instagram, error) {
+func ImportReader(r io.Reader, opts ...Option) (*Instagram, error) {
 	bytes, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
@@ -244,34 +428,52 @@ func ImportReader(r io.Reader) (*Instagram, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ImportConfig(config)
+	return ImportConfig(config, opts...)
 }
 
 // ImportConfig imports instagram configuration from a configuration object.
+// Configs exported by older versions of goinsta are migrated first.
 //
-// This function does not set proxy automatically. Use SetProxy after this call.
-func ImportConfig(config ConfigFile) (*Instagram, error) {
-	url, err := neturl.Parse(goInstaAPIUrl)
-	if err != nil {
+// The proxy of the config is set before opts are applied as in
+// NewWithOptions, so WithProxy or WithTransport override it.
+func ImportConfig(config ConfigFile, opts ...Option) (*Instagram, error) {
+	if err := config.Migrate(); err != nil {
 		return nil, err
 	}
-
 	inst := &Instagram{
+		hosts:     config.Hosts.withDefaults(),
 		user:      config.User,
-		dID:       config.DeviceID,
-		uuid:      config.UUID,
-		rankToken: config.RankToken,
-		token:     config.Token,
-		pid:       config.PhoneID,
+		locale:    config.Locale,
+		device:    *config.Device,
+		app:       *config.App,
+		created:   config.CreatedAt,
+		refreshed: config.RefreshedAt,
 		c: &http.Client{
 			Transport: &http.Transport{
 				Proxy: http.ProxyFromEnvironment,
 			},
 		},
 	}
-	inst.c.Jar, err = cookiejar.New(nil)
+	inst.dID.Set(config.DeviceID)
+	inst.uuid.Set(config.UUID)
+	inst.rankToken.Set(config.RankToken)
+	inst.token.Set(config.Token)
+	inst.pid.Set(config.PhoneID)
+	inst.adid.Set(config.AdID)
+	inst.challengeURL.Set(config.ChallengeURL)
+	if config.Proxy != nil {
+		if err := inst.SetProxy(config.Proxy.URL, config.Proxy.Insecure); err != nil {
+			return nil, err
+		}
+	}
+
+	err := inst.apply(opts)
 	if err != nil {
-		return inst, err
+		return nil, err
+	}
+	url, err := inst.hosts.apiURL()
+	if err != nil {
+		return nil, err
 	}
 	inst.c.Jar.SetCookies(url, config.Cookies)
 
@@ -283,69 +485,71 @@ func ImportConfig(config ConfigFile) (*Instagram, error) {
 }
 
 // Import imports instagram configuration
-//
-// This function does not set proxy automatically. Use SetProxy after this call.
-func Import(path string) (*Instagram, error) {
+func Import(path string, opts ...Option) (*Instagram, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	return ImportReader(f)
+	return ImportReader(f, opts...)
 }
 
-func (inst *Instagram) readMsisdnHeader() error {
+func (inst *Instagram) readMsisdnHeader(ctx context.Context) error {
 	data, err := json.Marshal(
 		map[string]string{
-			"device_id": inst.uuid,
+			"device_id": inst.uuid.Get(),
 		},
 	)
 	if err != nil {
 		return err
 	}
-	_, err = inst.sendRequest(
+	_, err = inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint:   urlMsisdnHeader,
 			IsPost:     true,
+			Login:      true,
+			Handshake:  true,
 			Connection: "keep-alive",
-			Query:      generateSignature(b2s(data)),
+			Query:      inst.generateSignature(b2s(data)),
 		},
 	)
 	return err
 }
 
-func (inst *Instagram) contactPrefill() error {
+func (inst *Instagram) contactPrefill(ctx context.Context) error {
 	data, err := json.Marshal(
 		map[string]string{
-			"phone_id":   inst.pid,
-			"_csrftoken": inst.token,
+			"phone_id":   inst.pid.Get(),
+			"_csrftoken": inst.token.Get(),
 			"usage":      "prefill",
 		},
 	)
 	if err != nil {
 		return err
 	}
-	_, err = inst.sendRequest(
+	_, err = inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint:   urlContactPrefill,
 			IsPost:     true,
+			Login:      true,
+			Handshake:  true,
 			Connection: "keep-alive",
-			Query:      generateSignature(b2s(data)),
+			Query:      inst.generateSignature(b2s(data)),
 		},
 	)
 	return err
 }
 
-func (inst *Instagram) zrToken() error {
-	_, err := inst.sendRequest(
+func (inst *Instagram) zrToken(ctx context.Context) error {
+	_, err := inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint:   urlZrToken,
 			IsPost:     false,
 			Connection: "keep-alive",
 			Query: map[string]string{
-				"device_id":        inst.dID,
+				"device_id":        inst.dID.Get(),
 				"token_hash":       "",
-				"custom_device_id": inst.uuid,
+				"custom_device_id": inst.uuid.Get(),
 				"fetch_reason":     "token_expired",
 			},
 		},
@@ -353,21 +557,23 @@ func (inst *Instagram) zrToken() error {
 	return err
 }
 
-func (inst *Instagram) sendAdID() error {
+func (inst *Instagram) sendAdID(ctx context.Context) error {
 	data, err := inst.prepareData(
 		map[string]interface{}{
-			"adid": inst.adid,
+			"adid": inst.adid.Get(),
 		},
 	)
 	if err != nil {
 		return err
 	}
-	_, err = inst.sendRequest(
+	_, err = inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint:   urlLogAttribution,
 			IsPost:     true,
+			Login:      true,
+			Handshake:  true,
 			Connection: "keep-alive",
-			Query:      generateSignature(data),
+			Query:      inst.generateSignature(data),
 		},
 	)
 	return err
@@ -376,135 +582,180 @@ func (inst *Instagram) sendAdID() error {
 // Login performs instagram login.
 //
 // Password will be deleted after login
+//
+// If the account is protected by two factor authentication, Login returns
+// an error matching ErrTwoFactorRequired: finish the login with
+// TwoFactorLogin or TwoFactorLoginTOTP. If instagram asks for a challenge,
+// Login returns an error matching ErrChallengeRequired: solve it with
+// Challenge.Solve.
 func (inst *Instagram) Login() error {
-	err := inst.readMsisdnHeader()
+	return inst.LoginContext(context.Background())
+}
+
+// LoginContext is the context-aware version of Login.
+func (inst *Instagram) LoginContext(ctx context.Context) error {
+	err := inst.readMsisdnHeader(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = inst.syncFeatures()
+	err = inst.syncFeatures(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = inst.zrToken()
+	err = inst.zrToken(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = inst.sendAdID()
+	err = inst.sendAdID(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = inst.contactPrefill()
+	err = inst.contactPrefill(ctx)
 	if err != nil {
 		return err
 	}
 
 	result, err := json.Marshal(
 		map[string]interface{}{
-			"guid":                inst.uuid,
+			"guid":                inst.uuid.Get(),
 			"login_attempt_count": 0,
-			"_csrftoken":          inst.token,
-			"device_id":           inst.dID,
-			"adid":                inst.adid,
-			"phone_id":            inst.pid,
+			"_csrftoken":          inst.token.Get(),
+			"device_id":           inst.dID.Get(),
+			"adid":                inst.adid.Get(),
+			"phone_id":            inst.pid.Get(),
 			"username":            inst.user,
-			"password":            inst.pass,
+			"password":            inst.pass.Get(),
 			"google_tokens":       "[]",
 		},
 	)
 	if err != nil {
 		return err
 	}
-	body, err := inst.sendRequest(
+	body, err := inst.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: urlLogin,
-			Query:    generateSignature(b2s(result)),
-			IsPost:   true,
-			Login:    true,
+			Endpoint:  urlLogin,
+			Query:     inst.generateSignature(b2s(result)),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
 		},
 	)
 	if err != nil {
+		var tfErr TwoFactorError
+		var chErr ChallengeError
+		if errors.As(err, &tfErr) {
+			inst.mu.Lock()
+			inst.twoFactor = &tfErr.Info
+			inst.mu.Unlock()
+			inst.logger().Info("two factor authentication required", "user", inst.user)
+		} else if errors.As(err, &chErr) && chErr.Challenge.APIPath != "" {
+			inst.Challenge.start(chErr.Challenge.APIPath)
+			inst.logger().Info("challenge required", "user", inst.user)
+		}
 		return err
 	}
-	inst.pass = ""
+	inst.pass.Set("")
+	inst.logger().Info("logged in", "user", inst.user)
 
 	// getting account data
 	res := accountResp{}
-	err = json.Unmarshal(body, &res)
+	err = inst.unmarshal(body, &res)
 	if err != nil {
 		return err
 	}
 
-	inst.Account = &res.Account
-	inst.Account.inst = inst
-	inst.rankToken = strconv.FormatInt(inst.Account.ID, 10) + "_" + inst.uuid
-	inst.zrToken()
+	inst.setAccount(&res.Account)
+	inst.zrToken(ctx)
+	inst.autoSave("login")
 
 	return err
 }
 
 // Logout closes current session
+//
+// The session saved in the session store is deleted only once instagram
+// closed it: if the request fails, the session is kept so that Logout can
+// be called again.
 func (inst *Instagram) Logout() error {
-	_, err := inst.sendSimpleRequest(urlLogout)
-	inst.c.Jar = nil
+	return inst.LogoutContext(context.Background())
+}
+
+// LogoutContext is the context-aware version of Logout.
+func (inst *Instagram) LogoutContext(ctx context.Context) error {
+	_, err := inst.sendSimpleRequest(ctx, urlLogout)
+	if err != nil {
+		inst.logger().Warn("cannot log out", "user", inst.user, "error", err)
+		return err
+	}
+	inst.logger().Info("logged out", "user", inst.user)
+	if store := inst.sessionStore(); store != nil {
+		if serr := store.Delete(inst.user); serr != nil {
+			inst.logger().Warn("cannot delete session", "user", inst.user, "error", serr)
+		}
+	}
+	inst.mu.Lock()
 	inst.c = nil
-	return err
+	inst.mu.Unlock()
+	return nil
 }
 
-func (inst *Instagram) syncFeatures() error {
+func (inst *Instagram) syncFeatures(ctx context.Context) error {
 	data, err := inst.prepareData(
 		map[string]interface{}{
-			"id":          inst.uuid,
-			"experiments": goInstaExperiments,
+			"id":          inst.uuid.Get(),
+			"experiments": inst.app.Experiments,
 		},
 	)
 	if err != nil {
 		return err
 	}
 
-	_, err = inst.sendRequest(
+	_, err = inst.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: urlQeSync,
-			Query:    generateSignature(data),
-			IsPost:   true,
-			Login:    true,
+			Endpoint:  urlQeSync,
+			Query:     inst.generateSignature(data),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
 		},
 	)
 	return err
 }
 
-func (inst *Instagram) megaphoneLog() error {
+func (inst *Instagram) megaphoneLog(ctx context.Context) error {
 	data, err := inst.prepareData(
 		map[string]interface{}{
-			"id":        inst.Account.ID,
+			"id":        inst.accountID(),
 			"type":      "feed_aysf",
 			"action":    "seen",
 			"reason":    "",
-			"device_id": inst.dID,
-			"uuid":      generateMD5Hash(string(time.Now().Unix())),
+			"device_id": inst.dID.Get(),
+			"uuid":      generateMD5Hash(strconv.FormatInt(time.Now().Unix(), 10)),
 		},
 	)
 	if err != nil {
 		return err
 	}
-	_, err = inst.sendRequest(
+	_, err = inst.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: urlMegaphoneLog,
-			Query:    generateSignature(data),
-			IsPost:   true,
-			Login:    true,
+			Endpoint:  urlMegaphoneLog,
+			Query:     inst.generateSignature(data),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
 		},
 	)
 	return err
 }
 
-func (inst *Instagram) expose() error {
+func (inst *Instagram) expose(ctx context.Context) error {
 	data, err := inst.prepareData(
 		map[string]interface{}{
-			"id":         inst.Account.ID,
+			"id":         inst.accountID(),
 			"experiment": "ig_android_profile_contextual_feed",
 		},
 	)
@@ -512,11 +763,13 @@ func (inst *Instagram) expose() error {
 		return err
 	}
 
-	_, err = inst.sendRequest(
+	_, err = inst.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: urlExpose,
-			Query:    generateSignature(data),
-			IsPost:   true,
+			Endpoint:  urlExpose,
+			Query:     inst.generateSignature(data),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
 		},
 	)
 
@@ -529,9 +782,14 @@ func (inst *Instagram) expose() error {
 //
 // See example: examples/media/like.go
 func (inst *Instagram) GetMedia(o interface{}) (*FeedMedia, error) {
+	return inst.GetMediaContext(context.Background(), o)
+}
+
+// GetMediaContext is the context-aware version of GetMedia.
+func (inst *Instagram) GetMediaContext(ctx context.Context, o interface{}) (*FeedMedia, error) {
 	media := &FeedMedia{
 		inst:   inst,
 		NextID: o,
 	}
-	return media, media.Sync()
+	return media, media.SyncContext(ctx)
 }
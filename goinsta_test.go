@@ -0,0 +1,147 @@
+package goinsta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestConcurrentUse is meant to be run with the race detector.
+func TestConcurrentUse(t *testing.T) {
+	var hits int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&hits, 1)
+		http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: fmt.Sprint("token", n), Path: "/"})
+		w.Write([]byte(`{"status":"ok","user":{"pk":1,"username":"user"}}`))
+	}))
+	defer srv.Close()
+
+	insta := New("user", "pass")
+	if err := insta.SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	insta.setAccount(&Account{ID: 1, Username: "user"})
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				data, err := insta.prepareData()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				_, err = insta.sendRequest(context.Background(), &reqOptions{
+					Endpoint: urlTimeline,
+					IsPost:   true,
+					Query:    insta.generateSignature(data),
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 10; j++ {
+			insta.SetHTTPTransport(http.DefaultTransport)
+			insta.SetLogger(nil)
+			insta.SetMetrics(NewMemoryMetrics())
+			insta.SetLimiter(NewBucketLimiter(map[Category]Budget{
+				CategoryRead: {Requests: 1000, Per: time.Second, Burst: 1000},
+			}))
+			insta.SetRetryPolicy(DefaultRetryPolicy())
+			insta.SetDeviceID(generateDeviceID(generateMD5Hash(fmt.Sprint(j))))
+			if err := Export(insta, &bytes.Buffer{}); err != nil {
+				t.Error(err)
+				return
+			}
+			// the account is updated while requests read it
+			if err := insta.account().Sync(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if want := int64(workers*10 + 10); hits != want {
+		t.Fatalf("got %d requests, want %d", hits, want)
+	}
+}
+
+func TestAccountUpdatedInPlace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok","user":{"pk":1,"username":"user","biography":"synced"}}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	insta.setAccount(&Account{ID: 1, Username: "user"})
+	acc := insta.Account
+	if err := acc.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if acc.Biography != "synced" || insta.Account != acc {
+		t.Fatalf("account not updated in place: %q", acc.Biography)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	media := &FeedMedia{inst: insta, endpoint: urlTimeline}
+	if media.NextContext(ctx) {
+		t.Fatal("got a page from a blocked server")
+	}
+	if err := media.Error(); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	var photo bytes.Buffer
+	png.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 4, 3)))
+	_, err = insta.UploadPhotoContext(ctx, &photo, "caption", 87, 0)
+	if err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	var netErr networkError
+	if errors.As(err, &netErr) {
+		t.Fatal("cancellation reported as a network error")
+	}
+}
@@ -0,0 +1,1165 @@
+package goinstatest
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routes are the endpoints served by the server (see goinsta's const.go).
+var routes = []route{
+	// login
+	{"POST", "accounts/read_msisdn_header/", true, (*Server).handleOK},
+	{"POST", "accounts/contact_point_prefill/", true, (*Server).handleOK},
+	{"GET", "zr/token/result/", true, (*Server).handleZrToken},
+	{"POST", "qe/sync/", true, (*Server).handleQeSync},
+	{"POST", "attribution/log_attribution/", true, (*Server).handleOK},
+	{"POST", "accounts/login/", true, (*Server).handleLogin},
+	{"POST", "accounts/two_factor_login/", true, (*Server).handleTwoFactorLogin},
+	{"POST", "accounts/send_two_factor_login_sms/", true, (*Server).handleTwoFactorSMS},
+	{"GET", "challenge/{}/{}/", true, (*Server).handleChallengeState},
+	{"POST", "challenge/{}/{}/", true, (*Server).handleChallenge},
+	{"POST", "challenge/replay/{}/{}/", true, (*Server).handleChallengeReplay},
+	{"GET", "accounts/logout/", false, (*Server).handleLogout},
+	{"GET", "friendships/autocomplete_user_list/", false, (*Server).handleAutoComplete},
+	{"POST", "megaphone/log/", false, (*Server).handleOK},
+	{"POST", "qe/expose/", false, (*Server).handleOK},
+
+	// account
+	{"GET", "accounts/current_user/", false, (*Server).handleCurrentUser},
+	{"POST", "accounts/change_password/", false, (*Server).handleChangePassword},
+	{"POST", "accounts/set_private/", false, (*Server).handleSetPrivate},
+	{"POST", "accounts/set_public/", false, (*Server).handleSetPublic},
+	{"POST", "accounts/remove_profile_picture/", false, (*Server).handleCurrentUser},
+	{"POST", "accounts/set_biography/", false, (*Server).handleSetBiography},
+	{"POST", "accounts/edit_profile", false, (*Server).handleEditProfile},
+	{"GET", "feed/saved/", false, (*Server).handleSaved},
+	{"GET", "feed/liked/", false, (*Server).handleLiked},
+	{"GET", "feed/only_me_feed/", false, (*Server).handleEmptyFeed},
+
+	// friendships
+	{"GET", "friendships/{}/followers/", false, (*Server).handleFollowers},
+	{"GET", "friendships/{}/following/", false, (*Server).handleFollowing},
+	{"GET", "friendships/show/{}/", false, (*Server).handleFriendship},
+	{"GET", "friendships/pending/", false, (*Server).handlePending},
+	{"POST", "friendships/create/{}/", false, (*Server).handleFollow},
+	{"POST", "friendships/destroy/{}/", false, (*Server).handleUnfollow},
+	{"POST", "friendships/block/{}/", false, (*Server).handleBlock},
+	{"POST", "friendships/unblock/{}/", false, (*Server).handleUnblock},
+	{"POST", "friendships/mute_posts_or_story_from_follow/", false, (*Server).handleMute},
+	{"POST", "friendships/unmute_posts_or_story_from_follow/", false, (*Server).handleUnmute},
+
+	// users
+	{"GET", "users/{}/usernameinfo/", false, (*Server).handleUserByName},
+	{"GET", "users/{}/info/", false, (*Server).handleUserByID},
+	{"GET", "users/blocked_list/", false, (*Server).handleBlockedList},
+	{"GET", "users/search/", false, (*Server).handleSearchUsers},
+	{"GET", "feed/user/{}/", false, (*Server).handleUserFeed},
+	{"GET", "feed/user/{}/reel_media/", false, (*Server).handleUserStories},
+	{"GET", "usertags/{}/feed/", false, (*Server).handleEmptyFeed},
+	{"GET", "highlights/{}/highlights_tray/", false, (*Server).handleEmptyTray},
+
+	// timeline
+	{"POST", "feed/timeline/", false, (*Server).handleTimeline},
+	{"GET", "feed/reels_tray/", false, (*Server).handleEmptyTray},
+	{"POST", "feed/reels_media/", false, (*Server).handleReelsMedia},
+
+	// search
+	{"GET", "tags/search/", false, (*Server).handleSearchTags},
+	{"GET", "location_search/", false, (*Server).handleSearchLocation},
+	{"GET", "fbsearch/topsearch/", false, (*Server).handleTopSearch},
+
+	// feeds
+	{"GET", "feed/location/{}/", false, (*Server).handleEmptyFeed},
+	{"POST", "locations/{}/sections/", false, (*Server).handleLocationSections},
+	{"GET", "feed/tag/{}/", false, (*Server).handleTagFeed},
+
+	// media
+	{"GET", "media/{}/info/", false, (*Server).handleMediaInfo},
+	{"POST", "media/{}/delete/", false, (*Server).handleMediaDelete},
+	{"POST", "media/{}/like/", false, (*Server).handleLike},
+	{"POST", "media/{}/unlike/", false, (*Server).handleUnlike},
+	{"POST", "media/{}/save/", false, (*Server).handleSave},
+	{"POST", "media/seen/", false, (*Server).handleOK},
+	{"GET", "media/{}/likers/", false, (*Server).handleLikers},
+
+	// comments
+	{"POST", "media/{}/comment/", false, (*Server).handleCommentAdd},
+	{"POST", "media/{}/comment/{}/delete/", false, (*Server).handleCommentDelete},
+	{"GET", "media/{}/comments/", false, (*Server).handleComments},
+	{"POST", "media/{}/disable_comments/", false, (*Server).handleDisableComments},
+	{"POST", "media/{}/enable_comments/", false, (*Server).handleEnableComments},
+	{"POST", "media/{}/comment_like/", false, (*Server).handleCommentLike},
+	{"POST", "media/{}/comment_unlike/", false, (*Server).handleCommentUnlike},
+
+	// activity
+	{"GET", "news/", false, (*Server).handleNews},
+	{"GET", "news/inbox/", false, (*Server).handleNewsInbox},
+
+	// inbox
+	{"GET", "direct_v2/inbox/", false, (*Server).handleInbox},
+	{"GET", "direct_v2/pending_inbox/", false, (*Server).handlePendingInbox},
+	{"POST", "direct_v2/threads/broadcast/text/", false, (*Server).handleBroadcastText},
+	{"POST", "direct_v2/threads/broadcast/like/", false, (*Server).handleBroadcastLike},
+	{"POST", "direct_v2/threads/broadcast/reel_share/", false, (*Server).handleBroadcastReelShare},
+	{"GET", "direct_v2/threads/{}/", false, (*Server).handleThread},
+	{"POST", "direct_v2/threads/{}/mute/", false, (*Server).handleThreadMute},
+	{"POST", "direct_v2/threads/{}/unmute/", false, (*Server).handleThreadUnmute},
+
+	// tags
+	{"GET", "tags/{}/info/", false, (*Server).handleTagInfo},
+	{"GET", "tags/{}/story/", false, (*Server).handleTagStory},
+	{"GET", "tags/{}/ranked_sections/", false, (*Server).handleTagSections},
+
+	// upload
+	{"POST", "upload/photo/", false, (*Server).handleUploadPhoto},
+	{"POST", "rupload_igphoto/{}", false, (*Server).handleUploadPhoto},
+	{"POST", "media/configure/", false, (*Server).handleConfigure},
+	{"POST", "media/configure_sidecar/", false, (*Server).handleConfigureSidecar},
+}
+
+func ok(o object) (int, interface{}) {
+	if o == nil {
+		o = object{}
+	}
+	o["status"] = "ok"
+	return http.StatusOK, o
+}
+
+func fail(status int, message string) (int, interface{}) {
+	return status, failure(message, "")
+}
+
+var (
+	userNotFound     = object{"message": "User not found", "status": "fail"}
+	mediaNotFound    = object{"message": "Media not found or unavailable", "status": "fail"}
+	notAuthorized    = object{"message": "Not authorized to view user", "status": "fail"}
+	commentsLimited  = object{"message": "Comments on this post have been limited", "status": "fail"}
+	threadNotFound   = object{"message": "Thread not found", "status": "fail"}
+	challengeMissing = object{"message": "Challenge not found", "status": "fail"}
+)
+
+// userArg returns the user whose id is the i-th wildcard of c, or nil if
+// the viewer cannot see it.
+func (s *Server) userArg(c *call, i int) *user {
+	id, _ := strconv.ParseInt(c.args[i], 10, 64)
+	u := s.users[id]
+	if u == nil || !s.visible(c.viewer, u) {
+		return nil
+	}
+	return u
+}
+
+// mediaArg returns the media of the i-th wildcard of c, or nil if the
+// viewer cannot see it.
+func (s *Server) mediaArg(c *call, i int) *media {
+	m := s.findMedia(c.args[i])
+	if m == nil {
+		return nil
+	}
+	owner := s.users[m.UserID]
+	if owner == nil || !s.visible(c.viewer, owner) || !s.canView(c.viewer, owner) {
+		return nil
+	}
+	return m
+}
+
+func (s *Server) handleOK(c *call) (int, interface{}) {
+	return ok(nil)
+}
+
+func (s *Server) handleZrToken(c *call) (int, interface{}) {
+	return ok(object{"token": object{"ttl": 3600, "features": object{}}})
+}
+
+func (s *Server) handleQeSync(c *call) (int, interface{}) {
+	return ok(object{"experiments": []object{}})
+}
+
+func (s *Server) handleLogin(c *call) (int, interface{}) {
+	u := s.byName(c.params["username"])
+	if u == nil {
+		o := failure("The username you entered doesn't appear to belong to an account. Please check your username and try again.", "invalid_user")
+		o["invalid_credentials"] = true
+		return http.StatusBadRequest, o
+	}
+	if u.Password != c.params["password"] {
+		o := failure("The password you entered is incorrect. Please try again.", "bad_password")
+		o["invalid_credentials"] = true
+		return http.StatusBadRequest, o
+	}
+	if ch := s.challenges[u.ID]; ch != nil && !ch.solved {
+		return http.StatusBadRequest, object{
+			"message": "challenge_required",
+			"challenge": object{
+				"url":                 strings.TrimSuffix(s.URL(), "/") + ch.apiPath(),
+				"api_path":            ch.apiPath(),
+				"hide_webview_header": true,
+				"lock":                true,
+				"logout":              false,
+				"native_flow":         true,
+			},
+			"status":     "fail",
+			"error_type": "checkpoint_challenge_required",
+		}
+	}
+	if tf := s.twoFactors[u.ID]; tf != nil {
+		tf.identifier = randomHex(8)
+		msg := "Enter the code generated by your authentication app."
+		if tf.Code != "" {
+			tf.sent++
+			msg = "Enter the code we sent to your number ending in " + tf.PhoneNumber[len(tf.PhoneNumber)-2:] + "."
+		}
+		o := failure(msg, "two_factor_required")
+		o["two_factor_required"] = true
+		o["two_factor_info"] = tf.info(u)
+		o["phone_verification_settings"] = tf.settings()
+		return http.StatusBadRequest, o
+	}
+	return s.loggedIn(c, u, nil)
+}
+
+// loggedIn starts a session of u and answers the login request.
+func (s *Server) loggedIn(c *call, u *user, o object) (int, interface{}) {
+	s.startSession(c, u)
+	if o == nil {
+		o = object{}
+	}
+	o["logged_in_user"] = s.accountJSON(u)
+	return ok(o)
+}
+
+func (s *Server) handleTwoFactorLogin(c *call) (int, interface{}) {
+	u := s.byName(c.params["username"])
+	if u == nil || s.twoFactors[u.ID] == nil {
+		return http.StatusBadRequest, failure("Invalid user", "invalid_user")
+	}
+	tf := s.twoFactors[u.ID]
+	if tf.identifier == "" || c.params["two_factor_identifier"] != tf.identifier {
+		return http.StatusBadRequest, failure("Invalid two factor identifier. Please log in again.", "invalid_identifier")
+	}
+	if !tf.valid(c.params["verification_method"], c.params["verification_code"]) {
+		return http.StatusBadRequest, failure("Please check the security code and try again.", "sms_code_validation_code_invalid")
+	}
+	tf.identifier = ""
+	return s.loggedIn(c, u, nil)
+}
+
+func (s *Server) handleTwoFactorSMS(c *call) (int, interface{}) {
+	u := s.byName(c.params["username"])
+	if u == nil || s.twoFactors[u.ID] == nil {
+		return http.StatusBadRequest, failure("Invalid user", "invalid_user")
+	}
+	tf := s.twoFactors[u.ID]
+	if tf.identifier == "" || c.params["two_factor_identifier"] != tf.identifier {
+		return http.StatusBadRequest, failure("Invalid two factor identifier. Please log in again.", "invalid_identifier")
+	}
+	if tf.Code == "" {
+		return http.StatusBadRequest, failure("SMS two factor authentication is not enabled.", "sms_two_factor_off")
+	}
+	tf.sent++
+	return ok(object{
+		"two_factor_info":             tf.info(u),
+		"phone_verification_settings": tf.settings(),
+	})
+}
+
+// challengeArg returns the unsolved challenge of the wildcards of c.
+func (s *Server) challengeArg(c *call) *challenge {
+	id, _ := strconv.ParseInt(c.args[0], 10, 64)
+	ch := s.challenges[id]
+	if ch == nil || ch.solved || ch.nonce != c.args[1] {
+		return nil
+	}
+	return ch
+}
+
+func (s *Server) challengeJSON(ch *challenge) object {
+	u := s.users[ch.userID]
+	email := u.Username[:1] + "***@example.com"
+	data := object{}
+	switch ch.step {
+	case "select_verify_method":
+		data = object{"choice": "1", "email": email, "phone_number": "+** *** *** *42"}
+	case "delta_login_review":
+		data = object{"choice": "0"}
+	case "submit_phone":
+		data = object{"phone_number": ""}
+	case "submit_email":
+		data = object{"email": ""}
+	case "verify_email":
+		data = object{"security_code": "None", "resend_delay": 60, "contact_point": email, "form_type": "email"}
+	case "verify_phone":
+		data = object{"security_code": "None", "resend_delay": 60, "contact_point": "+** *** *** *42", "form_type": "phone_number"}
+	}
+	return object{
+		"step_name":  ch.step,
+		"step_data":  data,
+		"user_id":    ch.userID,
+		"nonce_code": ch.nonce,
+		"status":     "ok",
+	}
+}
+
+func (s *Server) handleChallengeState(c *call) (int, interface{}) {
+	ch := s.challengeArg(c)
+	if ch == nil {
+		return http.StatusNotFound, challengeMissing
+	}
+	return http.StatusOK, s.challengeJSON(ch)
+}
+
+func (s *Server) handleChallenge(c *call) (int, interface{}) {
+	ch := s.challengeArg(c)
+	if ch == nil {
+		return http.StatusNotFound, challengeMissing
+	}
+	verifying := ch.step == "verify_email" || ch.step == "verify_phone"
+	code, hasCode := c.params["security_code"]
+	switch {
+	case verifying && hasCode:
+		if code != ch.Code {
+			return fail(http.StatusBadRequest, "Please check the code we sent you and try again.")
+		}
+		if ch.ResetPassword {
+			ch.step = "change_password"
+			return http.StatusOK, s.challengeJSON(ch)
+		}
+	case ch.step == "select_verify_method" && c.params["choice"] != "":
+		ch.step = "verify_email"
+		if c.params["choice"] == "0" {
+			ch.step = "verify_phone"
+		}
+		ch.sent++
+		return http.StatusOK, s.challengeJSON(ch)
+	case ch.step == "submit_phone" && c.params["phone_number"] != "":
+		ch.step = "verify_phone"
+		ch.sent++
+		return http.StatusOK, s.challengeJSON(ch)
+	case ch.step == "submit_email" && c.params["email"] != "":
+		ch.step = "verify_email"
+		ch.sent++
+		return http.StatusOK, s.challengeJSON(ch)
+	case ch.step == "delta_login_review" && c.params["choice"] == "0":
+	case ch.step == "delta_login_review" && c.params["choice"] == "1":
+		ch.step = "change_password"
+		return http.StatusOK, s.challengeJSON(ch)
+	case ch.step == "change_password" && c.params["new_password1"] != "":
+		if c.params["new_password1"] != c.params["new_password2"] {
+			return fail(http.StatusBadRequest, "Please make sure both passwords match.")
+		}
+		s.users[ch.userID].Password = c.params["new_password1"]
+	default:
+		return fail(http.StatusBadRequest, "Invalid challenge step.")
+	}
+	ch.solved = true
+	return s.loggedIn(c, s.users[ch.userID], object{"action": "close"})
+}
+
+func (s *Server) handleChallengeReplay(c *call) (int, interface{}) {
+	ch := s.challengeArg(c)
+	if ch == nil {
+		return http.StatusNotFound, challengeMissing
+	}
+	if ch.step != "verify_email" && ch.step != "verify_phone" {
+		return fail(http.StatusBadRequest, "Invalid challenge step.")
+	}
+	ch.sent++
+	return http.StatusOK, s.challengeJSON(ch)
+}
+
+func (s *Server) handleLogout(c *call) (int, interface{}) {
+	if cookie, err := c.r.Cookie("sessionid"); err == nil {
+		delete(s.sessions, cookie.Value)
+	}
+	http.SetCookie(c.w, &http.Cookie{Name: "sessionid", Path: "/", MaxAge: -1})
+	return ok(nil)
+}
+
+func (s *Server) handleAutoComplete(c *call) (int, interface{}) {
+	return ok(object{
+		"users":   s.usersJSON(s.following(c.viewer.ID)),
+		"expires": time.Now().Add(time.Hour).Unix(),
+	})
+}
+
+func (s *Server) handleCurrentUser(c *call) (int, interface{}) {
+	return ok(object{"user": s.accountJSON(c.viewer)})
+}
+
+func (s *Server) handleChangePassword(c *call) (int, interface{}) {
+	if c.params["old_password"] != c.viewer.Password {
+		return fail(http.StatusBadRequest, "Your old password was entered incorrectly. Please enter it again.")
+	}
+	if c.params["new_password1"] == "" || c.params["new_password1"] != c.params["new_password2"] {
+		return fail(http.StatusBadRequest, "Please make sure both passwords match.")
+	}
+	c.viewer.Password = c.params["new_password1"]
+	return ok(nil)
+}
+
+func (s *Server) handleSetPrivate(c *call) (int, interface{}) {
+	c.viewer.Private = true
+	return s.handleCurrentUser(c)
+}
+
+func (s *Server) handleSetPublic(c *call) (int, interface{}) {
+	c.viewer.Private = false
+	for id := range c.viewer.requests {
+		if r := s.users[id]; r != nil {
+			r.following[c.viewer.ID] = true
+		}
+	}
+	c.viewer.requests = make(map[int64]bool)
+	return s.handleCurrentUser(c)
+}
+
+func (s *Server) handleSetBiography(c *call) (int, interface{}) {
+	c.viewer.Biography = c.params["raw_text"]
+	return ok(object{"user": object{"pk": c.viewer.ID, "biography": c.viewer.Biography}})
+}
+
+func (s *Server) handleEditProfile(c *call) (int, interface{}) {
+	if v, ok := c.params["biography"]; ok {
+		c.viewer.Biography = v
+	}
+	if v, ok := c.params["full_name"]; ok {
+		c.viewer.FullName = v
+	}
+	return s.handleCurrentUser(c)
+}
+
+// feedJSON renders the page of list starting after the max_id of c.
+func (s *Server) feedJSON(c *call, list []*media) object {
+	start, end, next := s.page(len(list), c.params["max_id"])
+	return object{
+		"items":                  s.mediaListJSON(list[start:end], c.viewer),
+		"num_results":            end - start,
+		"more_available":         next != "",
+		"next_max_id":            next,
+		"auto_load_more_enabled": true,
+	}
+}
+
+func (s *Server) handleSaved(c *call) (int, interface{}) {
+	list := s.mediaOf(func(m *media) bool { return c.viewer.saved[m.ID] })
+	items := []object{}
+	for _, m := range list {
+		items = append(items, object{"media": s.mediaJSON(m, c.viewer)})
+	}
+	return ok(object{
+		"items":          items,
+		"num_results":    len(items),
+		"more_available": false,
+	})
+}
+
+func (s *Server) handleLiked(c *call) (int, interface{}) {
+	return ok(s.feedJSON(c, s.mediaOf(func(m *media) bool { return m.likes[c.viewer.ID] })))
+}
+
+func (s *Server) handleEmptyFeed(c *call) (int, interface{}) {
+	return ok(s.feedJSON(c, nil))
+}
+
+func (s *Server) handleEmptyTray(c *call) (int, interface{}) {
+	return ok(object{"tray": []object{}, "broadcasts": []object{}})
+}
+
+// usersPage renders the page of users ids starting after the max_id of c.
+func (s *Server) usersPage(c *call, ids []int64) (int, interface{}) {
+	start, end, next := s.page(len(ids), c.params["max_id"])
+	return ok(object{
+		"users":       s.usersJSON(ids[start:end]),
+		"big_list":    next != "",
+		"next_max_id": next,
+		"page_size":   s.pageSize,
+	})
+}
+
+func (s *Server) handleFollowers(c *call) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil {
+		return http.StatusNotFound, userNotFound
+	}
+	if !s.canView(c.viewer, u) {
+		return http.StatusBadRequest, notAuthorized
+	}
+	return s.usersPage(c, s.followers(u.ID))
+}
+
+func (s *Server) handleFollowing(c *call) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil {
+		return http.StatusNotFound, userNotFound
+	}
+	if !s.canView(c.viewer, u) {
+		return http.StatusBadRequest, notAuthorized
+	}
+	return s.usersPage(c, s.following(u.ID))
+}
+
+func (s *Server) handleFriendship(c *call) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil {
+		return http.StatusNotFound, userNotFound
+	}
+	return ok(s.friendshipJSON(c.viewer, u))
+}
+
+func (s *Server) handlePending(c *call) (int, interface{}) {
+	return ok(object{"users": s.usersJSON(sortedIDs(c.viewer.requests))})
+}
+
+// friendship changes the relationship between the viewer and the user of c.
+func (s *Server) friendship(c *call, fn func(viewer, u *user)) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil || u.ID == c.viewer.ID {
+		return http.StatusNotFound, userNotFound
+	}
+	fn(c.viewer, u)
+	return ok(object{"friendship_status": s.friendshipJSON(c.viewer, u)})
+}
+
+func (s *Server) handleFollow(c *call) (int, interface{}) {
+	return s.friendship(c, func(viewer, u *user) {
+		if !viewer.blocked[u.ID] {
+			s.follow(viewer, u)
+		}
+	})
+}
+
+func (s *Server) handleUnfollow(c *call) (int, interface{}) {
+	return s.friendship(c, func(viewer, u *user) {
+		delete(viewer.following, u.ID)
+		delete(u.requests, viewer.ID)
+	})
+}
+
+func (s *Server) handleBlock(c *call) (int, interface{}) {
+	return s.friendship(c, func(viewer, u *user) {
+		viewer.blocked[u.ID] = true
+		delete(viewer.following, u.ID)
+		delete(u.following, viewer.ID)
+		delete(viewer.requests, u.ID)
+		delete(u.requests, viewer.ID)
+	})
+}
+
+func (s *Server) handleUnblock(c *call) (int, interface{}) {
+	return s.friendship(c, func(viewer, u *user) {
+		delete(viewer.blocked, u.ID)
+	})
+}
+
+// mute sets the muted posts and stories of the viewer as asked by c.
+func (s *Server) mute(c *call, muted bool) (int, interface{}) {
+	id, _ := strconv.ParseInt(c.params["user_id"], 10, 64)
+	u := s.users[id]
+	if u == nil || !s.visible(c.viewer, u) {
+		return http.StatusNotFound, userNotFound
+	}
+	if _, ok := c.params["target_posts_author_id"]; ok {
+		c.viewer.mutedPosts[u.ID] = muted
+	}
+	if _, ok := c.params["target_reel_author_id"]; ok {
+		c.viewer.mutedReel[u.ID] = muted
+	}
+	return ok(object{"friendship_status": s.friendshipJSON(c.viewer, u)})
+}
+
+func (s *Server) handleMute(c *call) (int, interface{}) {
+	return s.mute(c, true)
+}
+
+func (s *Server) handleUnmute(c *call) (int, interface{}) {
+	return s.mute(c, false)
+}
+
+func (s *Server) handleUserByName(c *call) (int, interface{}) {
+	u := s.byName(c.args[0])
+	if u == nil || !s.visible(c.viewer, u) {
+		return http.StatusNotFound, userNotFound
+	}
+	return ok(object{"user": s.userJSON(u, c.viewer)})
+}
+
+func (s *Server) handleUserByID(c *call) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil {
+		return http.StatusNotFound, userNotFound
+	}
+	return ok(object{"user": s.userJSON(u, c.viewer)})
+}
+
+func (s *Server) handleBlockedList(c *call) (int, interface{}) {
+	list := []object{}
+	for _, id := range sortedIDs(c.viewer.blocked) {
+		u := s.users[id]
+		list = append(list, object{
+			"user_id":         u.ID,
+			"username":        u.Username,
+			"full_name":       u.FullName,
+			"profile_pic_url": profilePicURL(u),
+		})
+	}
+	return ok(object{"blocked_list": list, "page_size": len(list)})
+}
+
+// searchUsers returns the users visible by viewer whose name contains q.
+func (s *Server) searchUsers(viewer *user, q string) []int64 {
+	q = strings.ToLower(q)
+	found := make(map[int64]bool)
+	for _, u := range s.users {
+		if !s.visible(viewer, u) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(u.Username), q) || strings.Contains(strings.ToLower(u.FullName), q) {
+			found[u.ID] = true
+		}
+	}
+	return sortedIDs(found)
+}
+
+func (s *Server) handleSearchUsers(c *call) (int, interface{}) {
+	ids := s.searchUsers(c.viewer, c.params["q"])
+	if count, err := strconv.Atoi(c.params["count"]); err == nil && count < len(ids) {
+		ids = ids[:count]
+	}
+	return ok(object{
+		"users":       s.usersJSON(ids),
+		"num_results": len(ids),
+		"has_more":    false,
+		"rank_token":  c.params["rank_token"],
+	})
+}
+
+func (s *Server) handleUserFeed(c *call) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil {
+		return http.StatusNotFound, userNotFound
+	}
+	if !s.canView(c.viewer, u) {
+		return http.StatusBadRequest, notAuthorized
+	}
+	return ok(s.feedJSON(c, s.mediaOf(func(m *media) bool { return m.UserID == u.ID })))
+}
+
+// reelJSON renders the (empty) story reel of u.
+func (s *Server) reelJSON(u *user) object {
+	return object{
+		"id":                strconv.FormatInt(u.ID, 10),
+		"user":              s.shortUserJSON(u),
+		"items":             []object{},
+		"reel_type":         "user_reel",
+		"latest_reel_media": 0,
+		"can_reply":         true,
+		"can_reshare":       true,
+	}
+}
+
+func (s *Server) handleUserStories(c *call) (int, interface{}) {
+	u := s.userArg(c, 0)
+	if u == nil {
+		return http.StatusNotFound, userNotFound
+	}
+	return ok(s.reelJSON(u))
+}
+
+func (s *Server) handleTimeline(c *call) (int, interface{}) {
+	viewer := c.viewer
+	list := s.mediaOf(func(m *media) bool {
+		return m.UserID == viewer.ID || viewer.following[m.UserID] && !viewer.mutedPosts[m.UserID]
+	})
+	feed := s.feedJSON(c, list)
+	items := []object{}
+	for _, item := range feed["items"].([]object) {
+		items = append(items, object{"media_or_ad": item})
+	}
+	delete(feed, "items")
+	feed["feed_items"] = items
+	return ok(feed)
+}
+
+func (s *Server) handleReelsMedia(c *call) (int, interface{}) {
+	var ids []string
+	json.Unmarshal([]byte(c.params["user_ids"]), &ids)
+	reels := object{}
+	for _, id := range ids {
+		uid, _ := strconv.ParseInt(id, 10, 64)
+		if u := s.users[uid]; u != nil && s.visible(c.viewer, u) {
+			reels[id] = s.reelJSON(u)
+		}
+	}
+	return ok(object{"reels": reels})
+}
+
+// tagMedia returns the media tagged with tag visible by viewer.
+func (s *Server) tagMedia(viewer *user, tag string) []*media {
+	return s.mediaOf(func(m *media) bool {
+		owner := s.users[m.UserID]
+		return hasHashtag(m.Caption, tag) && owner != nil &&
+			s.visible(viewer, owner) && s.canView(viewer, owner)
+	})
+}
+
+func tagID(name string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(name)))
+	return 17840000000000000 + int64(h.Sum32())
+}
+
+func (s *Server) handleSearchTags(c *call) (int, interface{}) {
+	q := strings.ToLower(strings.TrimPrefix(c.params["q"], "#"))
+	counts := make(map[string]int)
+	var names []string
+	for _, m := range s.mediaOf(func(*media) bool { return true }) {
+		for _, tag := range hashtags(m.Caption) {
+			if !strings.HasPrefix(tag, q) {
+				continue
+			}
+			if counts[tag] == 0 {
+				names = append(names, tag)
+			}
+			counts[tag]++
+		}
+	}
+	results := []object{}
+	for _, name := range names {
+		results = append(results, object{"id": tagID(name), "name": name, "media_count": counts[name]})
+	}
+	return ok(object{"results": results, "has_more": false, "rank_token": c.params["rank_token"]})
+}
+
+func (s *Server) handleSearchLocation(c *call) (int, interface{}) {
+	return ok(object{"venues": []object{}, "request_id": randomHex(8)})
+}
+
+func (s *Server) handleTopSearch(c *call) (int, interface{}) {
+	ids := s.searchUsers(c.viewer, c.params["query"])
+	return ok(object{
+		"users":              s.usersJSON(ids),
+		"places":             []object{},
+		"hashtags":           []object{},
+		"clear_client_cache": false,
+		"has_more":           false,
+		"rank_token":         c.params["rank_token"],
+	})
+}
+
+func (s *Server) handleLocationSections(c *call) (int, interface{}) {
+	return ok(object{"sections": []object{}, "more_available": false, "next_max_id": ""})
+}
+
+func (s *Server) handleTagFeed(c *call) (int, interface{}) {
+	feed := s.feedJSON(c, s.tagMedia(c.viewer, c.args[0]))
+	feed["ranked_items"] = []object{}
+	return ok(feed)
+}
+
+func (s *Server) handleMediaInfo(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	return ok(object{
+		"items":          []object{s.mediaJSON(m, c.viewer)},
+		"num_results":    1,
+		"more_available": false,
+	})
+}
+
+func (s *Server) handleMediaDelete(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	if m.UserID != c.viewer.ID {
+		return fail(http.StatusBadRequest, "You cannot delete this media.")
+	}
+	for _, cm := range s.commentsOf(m.ID) {
+		delete(s.comments, cm.ID)
+	}
+	delete(s.media, m.ID)
+	return ok(object{"did_delete": true})
+}
+
+// likeMedia sets the like of the viewer on the media of c.
+func (s *Server) likeMedia(c *call, liked bool) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	if liked {
+		m.likes[c.viewer.ID] = true
+	} else {
+		delete(m.likes, c.viewer.ID)
+	}
+	return ok(nil)
+}
+
+func (s *Server) handleLike(c *call) (int, interface{}) {
+	return s.likeMedia(c, true)
+}
+
+func (s *Server) handleUnlike(c *call) (int, interface{}) {
+	return s.likeMedia(c, false)
+}
+
+func (s *Server) handleSave(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	c.viewer.saved[m.ID] = true
+	return ok(nil)
+}
+
+func (s *Server) handleLikers(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	likers := sortedIDs(m.likes)
+	return ok(object{"users": s.usersJSON(likers), "user_count": len(likers)})
+}
+
+func (s *Server) handleCommentAdd(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	if m.CommentsDisabled {
+		return http.StatusBadRequest, commentsLimited
+	}
+	cm := s.addComment(m, c.viewer.ID, c.params["comment_text"])
+	return ok(object{"comment": s.commentJSON(cm, c.viewer)})
+}
+
+func (s *Server) handleCommentDelete(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	id, _ := strconv.ParseInt(c.args[1], 10, 64)
+	cm := s.comments[id]
+	if cm == nil || cm.MediaID != m.ID {
+		return fail(http.StatusNotFound, "Comment not found")
+	}
+	if cm.UserID != c.viewer.ID && m.UserID != c.viewer.ID {
+		return fail(http.StatusBadRequest, "You cannot delete this comment.")
+	}
+	delete(s.comments, id)
+	return ok(nil)
+}
+
+func (s *Server) handleComments(c *call) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	list := s.commentsOf(m.ID)
+	start, end, next := s.page(len(list), c.params["max_id"])
+	comments := []object{}
+	for _, cm := range list[start:end] {
+		comments = append(comments, s.commentJSON(cm, c.viewer))
+	}
+	o := object{
+		"comments":              comments,
+		"comment_count":         len(list),
+		"caption":               s.mediaJSON(m, c.viewer)["caption"],
+		"caption_is_edited":     false,
+		"has_more_comments":     next != "",
+		"comment_likes_enabled": true,
+		"threading_enabled":     false,
+	}
+	if next != "" {
+		o["next_max_id"] = next
+	}
+	return ok(o)
+}
+
+// commentsEnabled enables or disables the comments of the media of c.
+func (s *Server) commentsEnabled(c *call, enabled bool) (int, interface{}) {
+	m := s.mediaArg(c, 0)
+	if m == nil {
+		return http.StatusNotFound, mediaNotFound
+	}
+	if m.UserID != c.viewer.ID {
+		return fail(http.StatusBadRequest, "You cannot edit this media.")
+	}
+	m.CommentsDisabled = !enabled
+	return ok(nil)
+}
+
+func (s *Server) handleDisableComments(c *call) (int, interface{}) {
+	return s.commentsEnabled(c, false)
+}
+
+func (s *Server) handleEnableComments(c *call) (int, interface{}) {
+	return s.commentsEnabled(c, true)
+}
+
+// likeComment sets the like of the viewer on the comment of c.
+func (s *Server) likeComment(c *call, liked bool) (int, interface{}) {
+	id, _ := strconv.ParseInt(c.args[0], 10, 64)
+	cm := s.comments[id]
+	if cm == nil {
+		return fail(http.StatusNotFound, "Comment not found")
+	}
+	if liked {
+		cm.likes[c.viewer.ID] = true
+	} else {
+		delete(cm.likes, c.viewer.ID)
+	}
+	return ok(nil)
+}
+
+func (s *Server) handleCommentLike(c *call) (int, interface{}) {
+	return s.likeComment(c, true)
+}
+
+func (s *Server) handleCommentUnlike(c *call) (int, interface{}) {
+	return s.likeComment(c, false)
+}
+
+func (s *Server) handleNews(c *call) (int, interface{}) {
+	return ok(object{"stories": []object{}, "next_max_id": 0, "auto_load_more_enabled": false})
+}
+
+func (s *Server) handleNewsInbox(c *call) (int, interface{}) {
+	return ok(object{
+		"counts":                 object{"requests": len(c.viewer.requests), "photos_of_you": 0},
+		"friend_request_stories": []object{},
+		"new_stories":            []object{},
+		"old_stories":            []object{},
+		"next_max_id":            0,
+		"continuation_token":     0,
+	})
+}
+
+// threadItems returns a page of the messages of t, newest first, older
+// than the message cursor and reports whether there are older ones.
+func (s *Server) threadItems(t *thread, cursor string) ([]Message, bool) {
+	var items []Message
+	started := cursor == ""
+	for i := len(t.Messages) - 1; i >= 0; i-- {
+		msg := t.Messages[i]
+		if !started {
+			started = msg.ID == cursor
+			continue
+		}
+		if len(items) == s.pageSize {
+			return items, true
+		}
+		items = append(items, msg)
+	}
+	return items, false
+}
+
+func (s *Server) handleInbox(c *call) (int, interface{}) {
+	threads := s.threadsOf(c.viewer.ID)
+	start, end, next := s.page(len(threads), c.params["cursor"])
+	list := []object{}
+	for _, t := range threads[start:end] {
+		items, older := s.threadItems(t, "")
+		list = append(list, s.threadJSON(t, c.viewer, items, older))
+	}
+	return s.inboxJSON(list, next)
+}
+
+func (s *Server) inboxJSON(threads []object, next string) (int, interface{}) {
+	return ok(object{
+		"inbox": object{
+			"threads":               threads,
+			"has_older":             next != "",
+			"oldest_cursor":         next,
+			"unseen_count":          0,
+			"unseen_count_ts":       0,
+			"blended_inbox_enabled": false,
+		},
+		"seq_id":                 len(s.requests),
+		"snapshot_at_ms":         time.Now().UnixNano() / int64(time.Millisecond),
+		"pending_requests_total": 0,
+	})
+}
+
+func (s *Server) handlePendingInbox(c *call) (int, interface{}) {
+	return s.inboxJSON([]object{}, "")
+}
+
+// threadArg returns the thread of the first wildcard of c if the viewer is in it.
+func (s *Server) threadArg(c *call) *thread {
+	t := s.threads[c.args[0]]
+	if t == nil || !t.has(c.viewer.ID) {
+		return nil
+	}
+	return t
+}
+
+func (s *Server) handleThread(c *call) (int, interface{}) {
+	t := s.threadArg(c)
+	if t == nil {
+		return http.StatusNotFound, threadNotFound
+	}
+	items, older := s.threadItems(t, c.params["cursor"])
+	return ok(object{"thread": s.threadJSON(t, c.viewer, items, older)})
+}
+
+func (s *Server) threadMuted(c *call, muted bool) (int, interface{}) {
+	t := s.threadArg(c)
+	if t == nil {
+		return http.StatusNotFound, threadNotFound
+	}
+	t.Muted = muted
+	return ok(nil)
+}
+
+func (s *Server) handleThreadMute(c *call) (int, interface{}) {
+	return s.threadMuted(c, true)
+}
+
+func (s *Server) handleThreadUnmute(c *call) (int, interface{}) {
+	return s.threadMuted(c, false)
+}
+
+// broadcast adds a message to the thread of the thread_ids or the
+// recipient_users of c.
+func (s *Server) broadcast(c *call, kind, text string) (int, interface{}) {
+	var t *thread
+	var ids []string
+	json.Unmarshal([]byte(c.params["thread_ids"]), &ids)
+	for _, id := range ids {
+		if th := s.threads[id]; th != nil && th.has(c.viewer.ID) {
+			t = th
+		}
+	}
+	if t == nil {
+		var recipients [][]int64
+		json.Unmarshal([]byte(c.params["recipient_users"]), &recipients)
+		users := []int64{c.viewer.ID}
+		for _, r := range recipients {
+			for _, id := range r {
+				u := s.users[id]
+				if u == nil || !s.visible(c.viewer, u) {
+					return http.StatusNotFound, userNotFound
+				}
+				users = append(users, id)
+			}
+		}
+		if len(users) < 2 {
+			return fail(http.StatusBadRequest, "Missing recipients")
+		}
+		t = s.threadWith(users)
+	}
+	msg := s.addMessage(t, c.viewer.ID, kind, text)
+	return ok(object{
+		"action":      "item_ack",
+		"status_code": "200",
+		"payload": object{
+			"client_context": c.params["client_context"],
+			"item_id":        msg.ID,
+			"thread_id":      t.ID,
+			"timestamp":      strconv.FormatInt(msg.Time.UnixNano()/1000, 10),
+		},
+	})
+}
+
+func (s *Server) handleBroadcastText(c *call) (int, interface{}) {
+	return s.broadcast(c, "text", c.params["text"])
+}
+
+func (s *Server) handleBroadcastLike(c *call) (int, interface{}) {
+	return s.broadcast(c, "like", "")
+}
+
+func (s *Server) handleBroadcastReelShare(c *call) (int, interface{}) {
+	return s.broadcast(c, "reel_share", c.params["text"])
+}
+
+func (s *Server) handleTagInfo(c *call) (int, interface{}) {
+	name := strings.ToLower(c.args[0])
+	return ok(object{
+		"name":        name,
+		"id":          tagID(name),
+		"media_count": len(s.tagMedia(c.viewer, name)),
+	})
+}
+
+func (s *Server) handleTagStory(c *call) (int, interface{}) {
+	name := strings.ToLower(c.args[0])
+	return ok(object{"story": object{
+		"id":        "tag:" + name,
+		"items":     []object{},
+		"reel_type": "tag_reel",
+	}})
+}
+
+func (s *Server) handleTagSections(c *call) (int, interface{}) {
+	list := s.tagMedia(c.viewer, c.args[0])
+	start, end, next := s.page(len(list), c.params["max_id"])
+	medias := []object{}
+	for _, m := range list[start:end] {
+		medias = append(medias, object{"media": s.mediaJSON(m, c.viewer)})
+	}
+	page, _ := strconv.Atoi(c.params["page"])
+	return ok(object{
+		"sections": []object{{
+			"layout_type":    "media_grid",
+			"layout_content": object{"medias": medias},
+			"feed_type":      "media",
+		}},
+		"media_count":    len(list),
+		"more_available": next != "",
+		"next_max_id":    next,
+		"next_page":      page + 1,
+	})
+}
+
+func (s *Server) handleUploadPhoto(c *call) (int, interface{}) {
+	id := c.params["upload_id"]
+	if id == "" {
+		id = strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	}
+	s.uploads[id] = c.viewer.ID
+	return ok(object{"upload_id": id, "xsharing_nonces": object{}})
+}
+
+// useUpload consumes the upload id of the viewer.
+func (s *Server) useUpload(c *call, id string) bool {
+	if s.uploads[id] != c.viewer.ID || id == "" {
+		return false
+	}
+	delete(s.uploads, id)
+	return true
+}
+
+func (s *Server) handleConfigure(c *call) (int, interface{}) {
+	id := c.params["upload_id"]
+	if !s.useUpload(c, id) {
+		return fail(http.StatusBadRequest, "Upload not found")
+	}
+	m := s.addMedia(c.viewer.ID, c.params["caption"], 0)
+	return ok(object{"media": s.mediaJSON(m, c.viewer), "upload_id": id})
+}
+
+func (s *Server) handleConfigureSidecar(c *call) (int, interface{}) {
+	var children []struct {
+		UploadID string `json:"upload_id"`
+	}
+	json.Unmarshal([]byte(c.params["children_metadata"]), &children)
+	if len(children) == 0 {
+		return fail(http.StatusBadRequest, "Missing children")
+	}
+	for _, child := range children {
+		if !s.useUpload(c, child.UploadID) {
+			return fail(http.StatusBadRequest, "Upload not found")
+		}
+	}
+	m := s.addMedia(c.viewer.ID, c.params["caption"], len(children))
+	sidecarID, _ := strconv.ParseInt(c.params["client_sidecar_id"], 10, 64)
+	return ok(object{"media": s.mediaJSON(m, c.viewer), "client_sidecar_id": sidecarID})
+}
@@ -0,0 +1,519 @@
+package goinstatest
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User is an account of the fake server.
+type User struct {
+	// ID is set by AddUser when zero.
+	ID        int64
+	Username  string
+	Password  string
+	FullName  string
+	Biography string
+	Private   bool
+}
+
+// Media is a post of the fake server.
+type Media struct {
+	// ID is the media id, "<Pk>_<UserID>".
+	ID               string
+	Pk               int64
+	UserID           int64
+	Caption          string
+	TakenAt          time.Time
+	CommentsDisabled bool
+	// Children is the number of photos of an album, zero for single photos.
+	Children int
+}
+
+// Comment is a comment of a media.
+type Comment struct {
+	ID        int64
+	MediaID   string
+	UserID    int64
+	Text      string
+	CreatedAt time.Time
+}
+
+// Thread is a direct conversation.
+type Thread struct {
+	ID string
+	// Users are the participants of the thread.
+	Users []int64
+	// Messages are the messages of the thread, oldest first.
+	Messages []Message
+	Muted    bool
+}
+
+// Message is a message of a direct thread.
+type Message struct {
+	ID     string
+	UserID int64
+	// Type is "text", "like" or "reel_share".
+	Type string
+	Text string
+	Time time.Time
+}
+
+type user struct {
+	User
+	following  map[int64]bool
+	requests   map[int64]bool
+	blocked    map[int64]bool
+	mutedPosts map[int64]bool
+	mutedReel  map[int64]bool
+	saved      map[string]bool
+}
+
+type media struct {
+	Media
+	likes map[int64]bool
+}
+
+type comment struct {
+	Comment
+	likes map[int64]bool
+}
+
+type thread struct {
+	Thread
+}
+
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return 1000000000 + s.lastID
+}
+
+// AddUser adds u to the model and returns it with its ID set.
+// It panics if the username is already taken.
+func (s *Server) AddUser(u User) User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byName(u.Username) != nil {
+		panic("goinstatest: user " + u.Username + " already exists")
+	}
+	if u.ID == 0 {
+		u.ID = s.nextID()
+	}
+	s.users[u.ID] = &user{
+		User:       u,
+		following:  make(map[int64]bool),
+		requests:   make(map[int64]bool),
+		blocked:    make(map[int64]bool),
+		mutedPosts: make(map[int64]bool),
+		mutedReel:  make(map[int64]bool),
+		saved:      make(map[string]bool),
+	}
+	return u
+}
+
+// User returns the user username.
+func (s *Server) User(username string) (User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.byName(username); u != nil {
+		return u.User, true
+	}
+	return User{}, false
+}
+
+// UserByID returns the user id.
+func (s *Server) UserByID(id int64) (User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.users[id]; u != nil {
+		return u.User, true
+	}
+	return User{}, false
+}
+
+func (s *Server) byName(username string) *user {
+	for _, u := range s.users {
+		if strings.EqualFold(u.Username, username) {
+			return u
+		}
+	}
+	return nil
+}
+
+// Follow makes follower follow followee, even if followee is private.
+func (s *Server) Follow(follower, followee int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.users[follower]; u != nil {
+		u.following[followee] = true
+	}
+}
+
+// Following returns the ids of the users followed by id.
+func (s *Server) Following(id int64) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.following(id)
+}
+
+// Followers returns the ids of the users following id.
+func (s *Server) Followers(id int64) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.followers(id)
+}
+
+// PendingRequests returns the ids of the users who asked to follow the
+// private user id.
+func (s *Server) PendingRequests(id int64) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.users[id]; u != nil {
+		return sortedIDs(u.requests)
+	}
+	return nil
+}
+
+// AcceptRequest accepts the follow request sent by requester to id.
+func (s *Server) AcceptRequest(id, requester int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, r := s.users[id], s.users[requester]
+	if u == nil || r == nil || !u.requests[requester] {
+		return
+	}
+	delete(u.requests, requester)
+	r.following[id] = true
+}
+
+// Blocked returns the ids of the users blocked by id.
+func (s *Server) Blocked(id int64) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.users[id]; u != nil {
+		return sortedIDs(u.blocked)
+	}
+	return nil
+}
+
+func (s *Server) following(id int64) []int64 {
+	if u := s.users[id]; u != nil {
+		return sortedIDs(u.following)
+	}
+	return nil
+}
+
+func (s *Server) followers(id int64) []int64 {
+	ids := make(map[int64]bool)
+	for _, u := range s.users {
+		if u.following[id] {
+			ids[u.ID] = true
+		}
+	}
+	return sortedIDs(ids)
+}
+
+// follow makes viewer follow u, or asks to if u is private.
+func (s *Server) follow(viewer, u *user) {
+	if viewer.following[u.ID] {
+		return
+	}
+	if u.Private {
+		u.requests[viewer.ID] = true
+		return
+	}
+	viewer.following[u.ID] = true
+}
+
+// canView reports whether viewer can see the posts of u.
+func (s *Server) canView(viewer, u *user) bool {
+	return !u.Private || viewer.ID == u.ID || viewer.following[u.ID]
+}
+
+// visible reports whether viewer can see u at all.
+func (s *Server) visible(viewer, u *user) bool {
+	return !u.blocked[viewer.ID]
+}
+
+// AddMedia adds a photo of userID with caption and returns it.
+func (s *Server) AddMedia(userID int64, caption string) Media {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addMedia(userID, caption, 0).Media
+}
+
+func (s *Server) addMedia(userID int64, caption string, children int) *media {
+	pk := s.nextID()
+	m := &media{
+		Media: Media{
+			ID:       strconv.FormatInt(pk, 10) + "_" + strconv.FormatInt(userID, 10),
+			Pk:       pk,
+			UserID:   userID,
+			Caption:  caption,
+			TakenAt:  time.Now(),
+			Children: children,
+		},
+		likes: make(map[int64]bool),
+	}
+	s.media[m.ID] = m
+	return m
+}
+
+// Media returns the media id.
+func (s *Server) Media(id string) (Media, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m := s.findMedia(id); m != nil {
+		return m.Media, true
+	}
+	return Media{}, false
+}
+
+// UserMedia returns the media of userID, newest first.
+func (s *Server) UserMedia(userID int64) []Media {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Media
+	for _, m := range s.mediaOf(func(m *media) bool { return m.UserID == userID }) {
+		out = append(out, m.Media)
+	}
+	return out
+}
+
+// Likers returns the ids of the users who liked the media id.
+func (s *Server) Likers(id string) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m := s.findMedia(id); m != nil {
+		return sortedIDs(m.likes)
+	}
+	return nil
+}
+
+// findMedia returns the media of id, which can be the media id or its pk.
+func (s *Server) findMedia(id string) *media {
+	if m := s.media[id]; m != nil {
+		return m
+	}
+	for _, m := range s.media {
+		if strconv.FormatInt(m.Pk, 10) == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// mediaOf returns the media matching fn, newest first.
+func (s *Server) mediaOf(fn func(m *media) bool) []*media {
+	var out []*media
+	for _, m := range s.media {
+		if fn(m) {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Pk > out[j].Pk })
+	return out
+}
+
+// AddComment adds a comment of userID to the media mediaID and returns it.
+func (s *Server) AddComment(mediaID string, userID int64, text string) Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.findMedia(mediaID)
+	if m == nil {
+		panic("goinstatest: unknown media " + mediaID)
+	}
+	return s.addComment(m, userID, text).Comment
+}
+
+func (s *Server) addComment(m *media, userID int64, text string) *comment {
+	c := &comment{
+		Comment: Comment{
+			ID:        s.nextID(),
+			MediaID:   m.ID,
+			UserID:    userID,
+			Text:      text,
+			CreatedAt: time.Now(),
+		},
+		likes: make(map[int64]bool),
+	}
+	s.comments[c.ID] = c
+	return c
+}
+
+// Comments returns the comments of the media mediaID, oldest first.
+func (s *Server) Comments(mediaID string) []Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Comment
+	if m := s.findMedia(mediaID); m != nil {
+		for _, c := range s.commentsOf(m.ID) {
+			out = append(out, c.Comment)
+		}
+	}
+	return out
+}
+
+func (s *Server) commentsOf(mediaID string) []*comment {
+	var out []*comment
+	for _, c := range s.comments {
+		if c.MediaID == mediaID {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// AddThread creates a direct thread between users and returns it.
+func (s *Server) AddThread(users ...int64) Thread {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addThread(users).Thread
+}
+
+func (s *Server) addThread(users []int64) *thread {
+	t := &thread{
+		Thread: Thread{
+			ID:    strconv.FormatInt(s.nextID(), 10),
+			Users: append([]int64(nil), users...),
+		},
+	}
+	s.threads[t.ID] = t
+	return t
+}
+
+// SendMessage adds a text message of userID to the thread id and returns it.
+func (s *Server) SendMessage(id string, userID int64, text string) Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.threads[id]
+	if t == nil {
+		panic("goinstatest: unknown thread " + id)
+	}
+	return s.addMessage(t, userID, "text", text)
+}
+
+func (s *Server) addMessage(t *thread, userID int64, kind, text string) Message {
+	msg := Message{
+		ID:     strconv.FormatInt(s.nextID(), 10),
+		UserID: userID,
+		Type:   kind,
+		Text:   text,
+		Time:   time.Now(),
+	}
+	t.Messages = append(t.Messages, msg)
+	return msg
+}
+
+// Thread returns the direct thread id.
+func (s *Server) Thread(id string) (Thread, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t := s.threads[id]; t != nil {
+		return t.copy(), true
+	}
+	return Thread{}, false
+}
+
+// Threads returns the direct threads of userID, most recent first.
+func (s *Server) Threads(userID int64) []Thread {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Thread
+	for _, t := range s.threadsOf(userID) {
+		out = append(out, t.copy())
+	}
+	return out
+}
+
+func (t *thread) copy() Thread {
+	c := t.Thread
+	c.Users = append([]int64(nil), t.Users...)
+	c.Messages = append([]Message(nil), t.Messages...)
+	return c
+}
+
+func (t *thread) has(id int64) bool {
+	for _, u := range t.Users {
+		if u == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *thread) lastActivity() int64 {
+	if n := len(t.Messages); n > 0 {
+		return t.Messages[n-1].Time.UnixNano()
+	}
+	return 0
+}
+
+// threadsOf returns the threads of userID, most recent first.
+func (s *Server) threadsOf(userID int64) []*thread {
+	var out []*thread
+	for _, t := range s.threads {
+		if t.has(userID) {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].lastActivity(), out[j].lastActivity()
+		if a != b {
+			return a > b
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out
+}
+
+// threadWith returns the thread between exactly users, creating it if needed.
+func (s *Server) threadWith(users []int64) *thread {
+	for _, t := range s.threads {
+		if len(t.Users) != len(users) {
+			continue
+		}
+		same := true
+		for _, id := range users {
+			same = same && t.has(id)
+		}
+		if same {
+			return t
+		}
+	}
+	return s.addThread(users)
+}
+
+var hashtagRe = regexp.MustCompile(`#(\w+)`)
+
+// hashtags returns the lower-cased hashtags of caption.
+func hashtags(caption string) []string {
+	var tags []string
+	for _, m := range hashtagRe.FindAllStringSubmatch(caption, -1) {
+		tags = append(tags, strings.ToLower(m[1]))
+	}
+	return tags
+}
+
+func hasHashtag(caption, tag string) bool {
+	for _, t := range hashtags(caption) {
+		if t == strings.ToLower(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id, ok := range set {
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,223 @@
+package goinstatest
+
+import (
+	"strconv"
+)
+
+// The functions below render the model like the instagram API does,
+// as seen by the user viewer.
+
+func (s *Server) userJSON(u *user, viewer *user) object {
+	following := s.following(u.ID)
+	followers := s.followers(u.ID)
+	return object{
+		"pk":                            u.ID,
+		"username":                      u.Username,
+		"full_name":                     u.FullName,
+		"biography":                     u.Biography,
+		"is_private":                    u.Private,
+		"is_verified":                   false,
+		"profile_pic_url":               profilePicURL(u),
+		"has_anonymous_profile_picture": true,
+		"media_count":                   len(s.mediaOf(func(m *media) bool { return m.UserID == u.ID })),
+		"follower_count":                len(followers),
+		"following_count":               len(following),
+		"friendship_status":             s.friendshipJSON(viewer, u),
+	}
+}
+
+// shortUserJSON is the user rendered inside lists, media and comments.
+func (s *Server) shortUserJSON(u *user) object {
+	if u == nil {
+		return nil
+	}
+	return object{
+		"pk":              u.ID,
+		"username":        u.Username,
+		"full_name":       u.FullName,
+		"is_private":      u.Private,
+		"is_verified":     false,
+		"profile_pic_url": profilePicURL(u),
+	}
+}
+
+func (s *Server) usersJSON(ids []int64) []object {
+	out := []object{}
+	for _, id := range ids {
+		if u := s.users[id]; u != nil {
+			out = append(out, s.shortUserJSON(u))
+		}
+	}
+	return out
+}
+
+func (s *Server) accountJSON(u *user) object {
+	o := s.userJSON(u, u)
+	delete(o, "friendship_status")
+	o["email"] = u.Username + "@example.com"
+	o["phone_number"] = ""
+	o["allow_contacts_sync"] = false
+	return o
+}
+
+func (s *Server) friendshipJSON(viewer, u *user) object {
+	return object{
+		"following":        viewer.following[u.ID],
+		"followed_by":      u.following[viewer.ID],
+		"outgoing_request": u.requests[viewer.ID],
+		"incoming_request": viewer.requests[u.ID],
+		"blocking":         viewer.blocked[u.ID],
+		"is_private":       u.Private,
+		"muting":           viewer.mutedPosts[u.ID],
+		"is_muting_reel":   viewer.mutedReel[u.ID],
+	}
+}
+
+func (s *Server) mediaJSON(m *media, viewer *user) object {
+	owner := s.users[m.UserID]
+	comments := s.commentsOf(m.ID)
+	o := object{
+		"pk":                    m.Pk,
+		"id":                    m.ID,
+		"taken_at":              m.TakenAt.Unix(),
+		"device_timestamp":      m.TakenAt.UnixNano() / 1000,
+		"media_type":            1,
+		"code":                  "C" + strconv.FormatInt(m.Pk, 36),
+		"client_cache_key":      m.ID,
+		"user":                  s.shortUserJSON(owner),
+		"like_count":            len(m.likes),
+		"has_liked":             m.likes[viewer.ID],
+		"comment_count":         len(comments),
+		"comments_disabled":     m.CommentsDisabled,
+		"comment_likes_enabled": true,
+		"has_more_comments":     false,
+		"can_viewer_save":       true,
+		"photo_of_you":          false,
+		"image_versions2":       imagesJSON(m.ID),
+		"original_width":        1080,
+		"original_height":       1080,
+	}
+	if m.Caption != "" {
+		o["caption"] = object{
+			"pk":           m.Pk + 1,
+			"user_id":      m.UserID,
+			"text":         m.Caption,
+			"type":         1,
+			"created_at":   m.TakenAt.Unix(),
+			"content_type": "comment",
+			"status":       "Active",
+			"user":         s.shortUserJSON(owner),
+			"media_id":     m.Pk,
+		}
+	}
+	if m.Children > 0 {
+		o["media_type"] = 8
+		children := make([]object, m.Children)
+		for i := range children {
+			pk := m.Pk*100 + int64(i) + 1
+			id := strconv.FormatInt(pk, 10) + "_" + strconv.FormatInt(m.UserID, 10)
+			children[i] = object{
+				"pk":                 pk,
+				"id":                 id,
+				"media_type":         1,
+				"carousel_parent_id": m.ID,
+				"image_versions2":    imagesJSON(id),
+				"original_width":     1080,
+				"original_height":    1080,
+			}
+		}
+		o["carousel_media"] = children
+	}
+	return o
+}
+
+func (s *Server) mediaListJSON(list []*media, viewer *user) []object {
+	out := []object{}
+	for _, m := range list {
+		out = append(out, s.mediaJSON(m, viewer))
+	}
+	return out
+}
+
+func (s *Server) commentJSON(c *comment, viewer *user) object {
+	return object{
+		"pk":                 c.ID,
+		"text":               c.Text,
+		"type":               0,
+		"user_id":            c.UserID,
+		"user":               s.shortUserJSON(s.users[c.UserID]),
+		"created_at":         c.CreatedAt.Unix(),
+		"created_at_utc":     c.CreatedAt.Unix(),
+		"content_type":       "comment",
+		"status":             "Active",
+		"bit_flags":          0,
+		"comment_like_count": len(c.likes),
+		"has_liked_comment":  c.likes[viewer.ID],
+	}
+}
+
+// threadJSON renders the messages items of t (newest first) with the
+// paging fields.
+func (s *Server) threadJSON(t *thread, viewer *user, items []Message, hasOlder bool) object {
+	var users []int64
+	for _, id := range t.Users {
+		if id != viewer.ID {
+			users = append(users, id)
+		}
+	}
+	out := []object{}
+	for _, msg := range items {
+		out = append(out, messageJSON(msg))
+	}
+	o := object{
+		"thread_id":        t.ID,
+		"thread_v2_id":     t.ID,
+		"thread_title":     "",
+		"thread_type":      "private",
+		"users":            s.usersJSON(users),
+		"left_users":       []object{},
+		"items":            out,
+		"viewer_id":        viewer.ID,
+		"muted":            t.Muted,
+		"pending":          false,
+		"named":            false,
+		"last_activity_at": t.lastActivity() / 1000,
+		"has_older":        hasOlder,
+		"has_newer":        false,
+	}
+	if len(items) > 0 {
+		o["newest_cursor"] = items[0].ID
+		o["oldest_cursor"] = items[len(items)-1].ID
+	}
+	return o
+}
+
+func messageJSON(msg Message) object {
+	o := object{
+		"item_id":        msg.ID,
+		"user_id":        msg.UserID,
+		"timestamp":      msg.Time.UnixNano() / 1000,
+		"item_type":      msg.Type,
+		"client_context": msg.ID,
+	}
+	switch msg.Type {
+	case "like":
+		o["like"] = "❤️"
+	default:
+		o["text"] = msg.Text
+	}
+	return o
+}
+
+func imagesJSON(id string) object {
+	return object{
+		"candidates": []object{
+			{"width": 1080, "height": 1080, "url": "https://scontent.cdninstagram.com/" + id + ".jpg"},
+			{"width": 320, "height": 320, "url": "https://scontent.cdninstagram.com/" + id + "_s320.jpg"},
+		},
+	}
+}
+
+func profilePicURL(u *user) string {
+	return "https://scontent.cdninstagram.com/profile/" + strconv.FormatInt(u.ID, 10) + ".jpg"
+}
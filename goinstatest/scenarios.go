@@ -0,0 +1,219 @@
+package goinstatest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ahmdrz/goinsta/v2"
+)
+
+// Fault is an error answered by the server instead of serving a request.
+//
+// Messages and error types are those of instagram, e.g. "login_required",
+// "feedback_required" or "challenge_required" (see goinsta's Err* variables).
+type Fault struct {
+	// Method is the method of the failing requests. Empty matches any method.
+	Method string
+	// Endpoint is the endpoint of the failing requests, like "media/1_1/like/".
+	// It can be a path.Match pattern, like "media/*/like/".
+	Endpoint string
+	// Status is the HTTP status code of the response.
+	Status int
+	// Message is the message field of the response. Defaults to the status text.
+	Message string
+	// ErrorType is the error_type field of the response.
+	ErrorType string
+	// RetryAfter sets the Retry-After header of the response.
+	RetryAfter time.Duration
+	// Times is the number of requests failing. Zero or less fails every
+	// request until ClearFaults is called.
+	Times int
+}
+
+func (f *Fault) body() object {
+	msg := f.Message
+	if msg == "" {
+		msg = http.StatusText(f.Status)
+	}
+	return failure(msg, f.ErrorType)
+}
+
+// Inject makes the requests matching f fail.
+// Faults are checked in the order they were injected.
+func (s *Server) Inject(f Fault) {
+	if f.Status == 0 {
+		f.Status = http.StatusBadRequest
+	}
+	s.mu.Lock()
+	s.faults = append(s.faults, &f)
+	s.mu.Unlock()
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	s.faults = nil
+	s.mu.Unlock()
+}
+
+// fault returns the fault matching a request, consuming it.
+func (s *Server) fault(method, endpoint string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != method || !matchEndpoint(f.Endpoint, endpoint) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// Challenge is a security challenge asked on login.
+type Challenge struct {
+	// Step is the first step of the challenge: "select_verify_method"
+	// (default) asks to choose where the code is sent, "submit_phone" and
+	// "submit_email" ask for the contact point to send it to,
+	// "delta_login_review" asks to confirm the login ("It was me").
+	Step string
+	// Code is the security code to send in the verify step.
+	Code string
+	// ResetPassword asks for a new password after the verify step.
+	// Denying a reviewed login always does.
+	ResetPassword bool
+}
+
+// challenge is a challenge being solved.
+type challenge struct {
+	Challenge
+	userID int64
+	nonce  string
+	step   string
+	sent   int
+	solved bool
+}
+
+func (ch *challenge) apiPath() string {
+	return "/challenge/" + strconv.FormatInt(ch.userID, 10) + "/" + ch.nonce + "/"
+}
+
+// RequireChallenge makes the logins of username fail with
+// challenge_required until the challenge is solved.
+func (s *Server) RequireChallenge(username string, ch Challenge) {
+	if ch.Step == "" {
+		ch.Step = "select_verify_method"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.byName(username)
+	if u == nil {
+		panic("goinstatest: unknown user " + username)
+	}
+	s.challenges[u.ID] = &challenge{
+		Challenge: ch,
+		userID:    u.ID,
+		nonce:     randomHex(5),
+		step:      ch.Step,
+	}
+}
+
+// ChallengeCodesSent returns the number of security codes sent for the
+// last challenge of username, including resent ones.
+func (s *Server) ChallengeCodesSent(username string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.byName(username); u != nil && s.challenges[u.ID] != nil {
+		return s.challenges[u.ID].sent
+	}
+	return 0
+}
+
+// TwoFactor is the two factor authentication of an account.
+type TwoFactor struct {
+	// Code is the verification code sent by SMS. Leave it empty to
+	// disable SMS codes.
+	Code string
+	// Secret is the base32 key of the authenticator app, if enabled.
+	// The codes are checked with goinsta.TOTPCode.
+	Secret string
+	// PhoneNumber is the obfuscated phone number the codes are sent to.
+	PhoneNumber string
+}
+
+type twoFactor struct {
+	TwoFactor
+	identifier string
+	sent       int
+}
+
+// RequireTwoFactor enables two factor authentication on the account
+// username: logins with a valid password ask for the verification code.
+func (s *Server) RequireTwoFactor(username string, tf TwoFactor) {
+	if tf.PhoneNumber == "" {
+		tf.PhoneNumber = "**** *** 42"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.byName(username)
+	if u == nil {
+		panic("goinstatest: unknown user " + username)
+	}
+	s.twoFactors[u.ID] = &twoFactor{TwoFactor: tf}
+}
+
+// TwoFactorCodesSent returns the number of SMS codes sent to username
+// by logins and resend requests.
+func (s *Server) TwoFactorCodesSent(username string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.byName(username); u != nil && s.twoFactors[u.ID] != nil {
+		return s.twoFactors[u.ID].sent
+	}
+	return 0
+}
+
+func (tf *twoFactor) info(u *user) object {
+	return object{
+		"username":                   u.Username,
+		"pk":                         u.ID,
+		"two_factor_identifier":      tf.identifier,
+		"obfuscated_phone_number":    tf.PhoneNumber,
+		"sms_two_factor_on":          tf.Code != "",
+		"totp_two_factor_on":         tf.Secret != "",
+		"show_messenger_code_option": false,
+		"show_new_login_screen":      true,
+		"show_trusted_device_option": false,
+	}
+}
+
+// valid reports whether code is a valid verification code for method.
+func (tf *twoFactor) valid(method, code string) bool {
+	if method != string(goinsta.TwoFactorTOTP) {
+		return tf.Code != "" && code == tf.Code
+	}
+	if tf.Secret == "" {
+		return false
+	}
+	// accept the codes of the previous and next periods as instagram does
+	now := time.Now()
+	for _, t := range []time.Time{now.Add(-30 * time.Second), now, now.Add(30 * time.Second)} {
+		if want, err := goinsta.TOTPCode(tf.Secret, t); err == nil && code == want {
+			return true
+		}
+	}
+	return false
+}
+
+func (tf *twoFactor) settings() object {
+	return object{
+		"max_sms_count":            2,
+		"resend_sms_delay_sec":     60,
+		"robocall_after_max_sms":   true,
+		"robocall_count_down_time": 30,
+	}
+}
@@ -0,0 +1,400 @@
+// Package goinstatest provides an in-process fake of the instagram API to
+// test code built on goinsta without reaching i.instagram.com.
+//
+// A Server keeps an in-memory model of users, media, comments, direct
+// threads and follow relationships. Sessions created by Server.Client and
+// Server.Login talk to it:
+//
+//	srv := goinstatest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddUser(goinstatest.User{Username: "alice", Password: "secret"})
+//	bob := srv.AddUser(goinstatest.User{Username: "bob"})
+//	srv.AddMedia(bob.ID, "hello #golang")
+//
+//	insta, err := srv.Login("alice")
+//
+// Login challenges, two factor authentication and API errors can be
+// simulated with RequireChallenge, RequireTwoFactor and Inject.
+package goinstatest
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmdrz/goinsta/v2"
+)
+
+// defaultPageSize is the number of entries of a page of paginated endpoints.
+const defaultPageSize = 20
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	// Endpoint is the request path relative to the API host, like "media/1_1/like/".
+	Endpoint string
+	// Params are the query, form and signed_body values of the request.
+	Params map[string]string
+}
+
+// Server is a fake instagram API server.
+//
+// It is safe for concurrent use.
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	pageSize int
+	requests []Request
+	faults   []*Fault
+
+	// model
+	lastID     int64
+	users      map[int64]*user
+	media      map[string]*media
+	comments   map[int64]*comment
+	threads    map[string]*thread
+	sessions   map[string]int64
+	uploads    map[string]int64
+	challenges map[int64]*challenge
+	twoFactors map[int64]*twoFactor
+}
+
+// NewServer starts a fake instagram server. It must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		pageSize:   defaultPageSize,
+		users:      make(map[int64]*user),
+		media:      make(map[string]*media),
+		comments:   make(map[int64]*comment),
+		threads:    make(map[string]*thread),
+		sessions:   make(map[string]int64),
+		uploads:    make(map[string]int64),
+		challenges: make(map[int64]*challenge),
+		twoFactors: make(map[int64]*twoFactor),
+	}
+	s.srv = httptest.NewServer(s)
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL returns the base URL of the server, to be used with goinsta.WithBaseURL.
+func (s *Server) URL() string {
+	return s.srv.URL + "/"
+}
+
+// Client returns a session of username wired to the server.
+// The session is not logged in.
+//
+// opts are applied after the options pointing the session to the server.
+func (s *Server) Client(username, password string, opts ...goinsta.Option) (*goinsta.Instagram, error) {
+	opts = append([]goinsta.Option{
+		goinsta.WithBaseURL(s.URL()),
+		goinsta.WithTransport(s.srv.Client().Transport),
+	}, opts...)
+	return goinsta.NewWithOptions(username, password, opts...)
+}
+
+// Login returns a session of the user username logged in with the password
+// stored in the model.
+func (s *Server) Login(username string, opts ...goinsta.Option) (*goinsta.Instagram, error) {
+	u, ok := s.User(username)
+	if !ok {
+		return nil, fmt.Errorf("goinstatest: unknown user %s", username)
+	}
+	inst, err := s.Client(username, u.Password, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return inst, inst.Login()
+}
+
+// SetPageSize sets the number of entries returned by paginated endpoints.
+func (s *Server) SetPageSize(n int) {
+	if n <= 0 {
+		n = defaultPageSize
+	}
+	s.mu.Lock()
+	s.pageSize = n
+	s.mu.Unlock()
+}
+
+// Requests returns the requests received by the server, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests clears the requests returned by Requests.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	s.requests = nil
+	s.mu.Unlock()
+}
+
+// object is a JSON object of a response.
+type object map[string]interface{}
+
+// call is a request being served.
+type call struct {
+	w        http.ResponseWriter
+	r        *http.Request
+	endpoint string
+	params   map[string]string
+	// args are the values matching the wildcards of the route.
+	args []string
+	// viewer is the logged in user, nil on public routes.
+	viewer *user
+}
+
+// ServeHTTP serves instagram API requests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/")
+	for _, prefix := range []string{"api/v1/", "api/v2/"} {
+		endpoint = strings.TrimPrefix(endpoint, prefix)
+	}
+
+	params, err := readParams(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, failure(err.Error(), ""))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Endpoint: endpoint, Params: params})
+	if _, err := r.Cookie("csrftoken"); err != nil {
+		http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: randomHex(16), Path: "/"})
+	}
+
+	if f := s.fault(r.Method, endpoint); f != nil {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter/time.Second)))
+		}
+		writeJSON(w, f.Status, f.body())
+		return
+	}
+
+	rt, args := match(r.Method, endpoint)
+	if rt == nil {
+		writeJSON(w, http.StatusNotFound, failure("Page not found", ""))
+		return
+	}
+	c := &call{w: w, r: r, endpoint: endpoint, params: params, args: args}
+	if !rt.public {
+		c.viewer = s.viewer(r)
+		if c.viewer == nil {
+			writeJSON(w, http.StatusForbidden, object{
+				"message":       "login_required",
+				"logout_reason": 2,
+				"status":        "fail",
+			})
+			return
+		}
+	}
+	status, body := rt.handle(s, c)
+	writeJSON(w, status, body)
+}
+
+// viewer returns the user logged in by the session cookie of r.
+func (s *Server) viewer(r *http.Request) *user {
+	cookie, err := r.Cookie("sessionid")
+	if err != nil {
+		return nil
+	}
+	return s.users[s.sessions[cookie.Value]]
+}
+
+// startSession logs u in the session of c.
+func (s *Server) startSession(c *call, u *user) {
+	id := randomHex(16)
+	s.sessions[id] = u.ID
+	http.SetCookie(c.w, &http.Cookie{Name: "sessionid", Value: id, Path: "/", HttpOnly: true})
+	http.SetCookie(c.w, &http.Cookie{Name: "ds_user_id", Value: strconv.FormatInt(u.ID, 10), Path: "/"})
+}
+
+// page returns the bounds of the page of a list of n entries starting after
+// cursor maxID and the cursor of the next page ("" on the last one).
+func (s *Server) page(n int, maxID string) (start, end int, next string) {
+	start, _ = strconv.Atoi(maxID)
+	if start < 0 || start > n {
+		start = n
+	}
+	end = start + s.pageSize
+	if end >= n {
+		return start, n, ""
+	}
+	return start, end, strconv.Itoa(end)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// failure returns the body of an instagram error.
+func failure(message, errorType string) object {
+	o := object{
+		"message": message,
+		"status":  "fail",
+	}
+	if errorType != "" {
+		o["error_type"] = errorType
+	}
+	return o
+}
+
+// readParams merges the query, the form and the signed_body data of r.
+func readParams(r *http.Request) (map[string]string, error) {
+	params := make(map[string]string)
+	for k, v := range r.URL.Query() {
+		params[k] = strings.Join(v, ",")
+	}
+
+	body, err := requestBody(r)
+	if err != nil {
+		return nil, err
+	}
+	ct := r.Header.Get("Content-Type")
+	if strings.HasPrefix(ct, "multipart/") {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, err
+		}
+		for k, v := range r.MultipartForm.Value {
+			params[k] = strings.Join(v, ",")
+		}
+		return params, nil
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range form {
+		params[k] = strings.Join(v, ",")
+	}
+
+	if signed, ok := params["signed_body"]; ok {
+		i := strings.IndexByte(signed, '.')
+		d := json.NewDecoder(strings.NewReader(signed[i+1:]))
+		d.UseNumber()
+		data := make(map[string]interface{})
+		if err := d.Decode(&data); err != nil {
+			return nil, fmt.Errorf("invalid signed_body: %v", err)
+		}
+		for k, v := range data {
+			params[k] = stringValue(v)
+		}
+	}
+	return params, nil
+}
+
+// requestBody reads the body of r, decompressing it if needed.
+func requestBody(r *http.Request) ([]byte, error) {
+	var rd io.Reader = r.Body
+	switch r.Header.Get("Content-Encoding") {
+	case "gzip":
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		rd = zr
+	case "deflate":
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		if zr, err := zlib.NewReader(bytes.NewReader(b)); err == nil {
+			rd = zr
+		} else {
+			rd = flate.NewReader(bytes.NewReader(b))
+		}
+	}
+	return ioutil.ReadAll(rd)
+}
+
+func stringValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// route is an endpoint served by the server.
+type route struct {
+	method string
+	// pattern is the endpoint where {} matches any path segment.
+	pattern string
+	// public routes do not need a logged in session.
+	public bool
+	handle func(s *Server, c *call) (int, interface{})
+}
+
+// match returns the route serving endpoint and the values of its wildcards.
+func match(method, endpoint string) (*route, []string) {
+	segments := strings.Split(endpoint, "/")
+	for i := range routes {
+		rt := &routes[i]
+		if rt.method != method {
+			continue
+		}
+		pattern := strings.Split(rt.pattern, "/")
+		if len(pattern) != len(segments) {
+			continue
+		}
+		var args []string
+		for j, p := range pattern {
+			if p == "{}" && segments[j] != "" {
+				args = append(args, segments[j])
+			} else if p != segments[j] {
+				args = nil
+				break
+			}
+			if j == len(pattern)-1 {
+				return rt, args
+			}
+		}
+	}
+	return nil, nil
+}
+
+// matchEndpoint reports whether endpoint matches the path.Match pattern.
+func matchEndpoint(pattern, endpoint string) bool {
+	ok, _ := path.Match(pattern, endpoint)
+	return ok || pattern == endpoint
+}
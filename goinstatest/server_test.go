@@ -0,0 +1,459 @@
+package goinstatest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmdrz/goinsta/v2"
+)
+
+func newServer(t *testing.T) *Server {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddUser(User{Username: "alice", Password: "secret", FullName: "Alice"})
+	srv.AddUser(User{Username: "bob", Password: "hunter2", FullName: "Bob"})
+	return srv
+}
+
+func login(t *testing.T, srv *Server, username string) *goinsta.Instagram {
+	insta, err := srv.Login(username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return insta
+}
+
+func TestLogin(t *testing.T) {
+	srv := newServer(t)
+	alice, _ := srv.User("alice")
+
+	insta := login(t, srv, "alice")
+	if insta.Account.ID != alice.ID || insta.Account.Username != "alice" {
+		t.Fatalf("got account %d %s", insta.Account.ID, insta.Account.Username)
+	}
+	if _, err := insta.Profiles.ByName("bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	insta, err := srv.Client("alice", "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = insta.Login()
+	var apiErr *goinsta.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorType != "bad_password" {
+		t.Fatalf("got %v, want bad_password", err)
+	}
+}
+
+func TestLoginRequired(t *testing.T) {
+	srv := newServer(t)
+	insta, err := srv.Client("alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insta.Profiles.ByName("bob"); !errors.Is(err, goinsta.ErrLoginRequired) {
+		t.Fatalf("got %v, want ErrLoginRequired", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	srv := newServer(t)
+	insta := login(t, srv, "alice")
+	var session bytes.Buffer
+	if err := goinsta.Export(insta, &session); err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Logout(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cookies of the session are not valid anymore
+	imported, err := goinsta.ImportReader(&session, goinsta.WithBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := imported.Profiles.ByName("bob"); !errors.Is(err, goinsta.ErrLoginRequired) {
+		t.Fatalf("got %v after logout, want ErrLoginRequired", err)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	srv := newServer(t)
+	c := srv.AddUser(User{Username: "carol", Private: true})
+	insta := login(t, srv, "alice")
+
+	bob, err := insta.Profiles.ByName("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bob.Follow(); err != nil {
+		t.Fatal(err)
+	}
+	if !bob.Friendship.Following {
+		t.Fatal("not following bob")
+	}
+	alice, _ := srv.User("alice")
+	if got := srv.Followers(bob.ID); len(got) != 1 || got[0] != alice.ID {
+		t.Fatalf("got bob followers %v", got)
+	}
+
+	carol, err := insta.Profiles.ByName("carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	followers := carol.Followers()
+	if followers.Next() || !errors.Is(followers.Error(), goinsta.ErrPrivateAccount) {
+		t.Fatalf("got %v, want ErrPrivateAccount", followers.Error())
+	}
+	if err := carol.Follow(); err != nil {
+		t.Fatal(err)
+	}
+	if !carol.Friendship.OutgoingRequest || carol.Friendship.Following {
+		t.Fatalf("got friendship %+v, want a pending request", carol.Friendship)
+	}
+	srv.AcceptRequest(c.ID, alice.ID)
+	if err := carol.FriendShip(); err != nil {
+		t.Fatal(err)
+	}
+	if !carol.Friendship.Following {
+		t.Fatal("not following carol after the request was accepted")
+	}
+}
+
+func TestFollowersPagination(t *testing.T) {
+	srv := newServer(t)
+	srv.SetPageSize(3)
+	b, _ := srv.User("bob")
+	for i := 0; i < 8; i++ {
+		fan := srv.AddUser(User{Username: fmt.Sprint("fan", i)})
+		srv.Follow(fan.ID, b.ID)
+	}
+	insta := login(t, srv, "alice")
+	bob, err := insta.Profiles.ByName("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	pages := 0
+	users := bob.Followers()
+	for users.Next() {
+		pages++
+		for _, u := range users.Users {
+			seen[u.Username] = true
+		}
+	}
+	if !errors.Is(users.Error(), goinsta.ErrNoMore) {
+		t.Fatal(users.Error())
+	}
+	if len(seen) != 8 || pages != 3 {
+		t.Fatalf("got %d followers in %d pages", len(seen), pages)
+	}
+}
+
+func TestLikeAndComment(t *testing.T) {
+	srv := newServer(t)
+	bob, _ := srv.User("bob")
+	m := srv.AddMedia(bob.ID, "hello #golang")
+	insta := login(t, srv, "alice")
+
+	user, err := insta.Profiles.ByName("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := user.Feed()
+	feed.Next()
+	if len(feed.Items) != 1 || feed.Items[0].ID != m.ID {
+		t.Fatalf("got %d items, want media %s", len(feed.Items), m.ID)
+	}
+	item := &feed.Items[0]
+	if err := item.Like(); err != nil {
+		t.Fatal(err)
+	}
+	if err := item.Comment("nice"); err != nil {
+		t.Fatal(err)
+	}
+
+	alice, _ := srv.User("alice")
+	if got := srv.Likers(m.ID); len(got) != 1 || got[0] != alice.ID {
+		t.Fatalf("got likers %v", got)
+	}
+	comments := srv.Comments(m.ID)
+	if len(comments) != 1 || comments[0].Text != "nice" {
+		t.Fatalf("got comments %v", comments)
+	}
+}
+
+func TestInbox(t *testing.T) {
+	srv := newServer(t)
+	alice, _ := srv.User("alice")
+	bob, _ := srv.User("bob")
+	th := srv.AddThread(alice.ID, bob.ID)
+	srv.SendMessage(th.ID, bob.ID, "hi alice")
+	insta := login(t, srv, "alice")
+
+	if err := insta.Inbox.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if len(insta.Inbox.Conversations) != 1 {
+		t.Fatalf("got %d conversations", len(insta.Inbox.Conversations))
+	}
+	conv := &insta.Inbox.Conversations[0]
+	if len(conv.Items) != 1 || conv.Items[0].Text != "hi alice" {
+		t.Fatalf("got items %+v", conv.Items)
+	}
+	if err := conv.Send("hi bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	threads := srv.Threads(bob.ID)
+	if len(threads) != 1 || len(threads[0].Messages) != 2 || threads[0].Messages[1].Text != "hi bob" {
+		t.Fatalf("got threads %+v", threads)
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	srv := newServer(t)
+	srv.RequireChallenge("alice", Challenge{Code: "123456"})
+	insta, err := srv.Client("alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = insta.Login()
+	var chErr goinsta.ChallengeError
+	if !errors.Is(err, goinsta.ErrChallengeRequired) || !errors.As(err, &chErr) {
+		t.Fatalf("got %v, want ErrChallengeRequired", err)
+	}
+	if err := insta.Challenge.Process(chErr.Challenge.APIPath); err != nil {
+		t.Fatal(err)
+	}
+	if insta.Challenge.StepName != "verify_email" || srv.ChallengeCodesSent("alice") != 1 {
+		t.Fatalf("got step %s with %d codes sent", insta.Challenge.StepName, srv.ChallengeCodesSent("alice"))
+	}
+	if err := insta.Challenge.SendSecurityCode("000000"); err == nil {
+		t.Fatal("wrong code accepted")
+	}
+	if err := insta.Challenge.SendSecurityCode("123456"); err != nil {
+		t.Fatal(err)
+	}
+	if insta.Challenge.LoggedInUser == nil || insta.Challenge.LoggedInUser.Username != "alice" {
+		t.Fatal("challenge did not log in")
+	}
+
+	if err := insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// solver answers the challenges of the tests.
+type solver struct {
+	code     string
+	resend   int
+	password string
+	me       bool
+	steps    []string
+}
+
+func (s *solver) VerifyMethod(ctx context.Context, ch *goinsta.Challenge) (string, error) {
+	s.steps = append(s.steps, ch.StepName)
+	return goinsta.ChallengeChoicePhone, nil
+}
+
+func (s *solver) SecurityCode(ctx context.Context, ch *goinsta.Challenge) (string, error) {
+	s.steps = append(s.steps, ch.StepName)
+	if s.resend > 0 {
+		s.resend--
+		return "", goinsta.ErrChallengeResend
+	}
+	return s.code, nil
+}
+
+func (s *solver) PhoneNumber(ctx context.Context, ch *goinsta.Challenge) (string, error) {
+	s.steps = append(s.steps, ch.StepName)
+	return "+33 6 00 00 00 42", nil
+}
+
+func (s *solver) Email(ctx context.Context, ch *goinsta.Challenge) (string, error) {
+	s.steps = append(s.steps, ch.StepName)
+	return "alice@example.com", nil
+}
+
+func (s *solver) NewPassword(ctx context.Context, ch *goinsta.Challenge) (string, error) {
+	s.steps = append(s.steps, ch.StepName)
+	return s.password, nil
+}
+
+func (s *solver) ConfirmLogin(ctx context.Context, ch *goinsta.Challenge) (bool, error) {
+	s.steps = append(s.steps, ch.StepName)
+	return s.me, nil
+}
+
+func TestChallengeSolver(t *testing.T) {
+	srv := newServer(t)
+	srv.RequireChallenge("alice", Challenge{Step: "submit_phone", Code: "123456", ResetPassword: true})
+	store := goinsta.NewMemoryStore()
+	insta, err := srv.Client("alice", "secret", goinsta.WithSessionStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Login(); !errors.Is(err, goinsta.ErrChallengeRequired) {
+		t.Fatalf("got %v, want ErrChallengeRequired", err)
+	}
+
+	// the challenge is solved by a new process
+	insta, err = goinsta.LoadSession(store, "alice", goinsta.WithBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &solver{code: "123456", resend: 1, password: "n3w-secret"}
+	if err := insta.Challenge.Solve(s); err != nil {
+		t.Fatal(err)
+	}
+	if insta.Account == nil || insta.Account.Username != "alice" {
+		t.Fatal("challenge did not log in")
+	}
+	if got := strings.Join(s.steps, ","); got != "submit_phone,verify_phone,verify_phone,change_password" {
+		t.Fatalf("got steps %s", got)
+	}
+	if srv.ChallengeCodesSent("alice") != 2 {
+		t.Fatalf("got %d codes sent", srv.ChallengeCodesSent("alice"))
+	}
+	if u, _ := srv.User("alice"); u.Password != "n3w-secret" {
+		t.Fatal("password not changed")
+	}
+	if err := insta.Challenge.Solve(s); !errors.Is(err, goinsta.ErrNoChallenge) {
+		t.Fatalf("got %v solving a challenge twice, want ErrNoChallenge", err)
+	}
+}
+
+func TestChallengeLoginReview(t *testing.T) {
+	srv := newServer(t)
+	srv.RequireChallenge("alice", Challenge{Step: "delta_login_review"})
+	insta, err := srv.Client("alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Login(); !errors.Is(err, goinsta.ErrChallengeRequired) {
+		t.Fatalf("got %v, want ErrChallengeRequired", err)
+	}
+	s := &solver{me: false, password: "n3w-secret"}
+	if err := insta.Challenge.Solve(s); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(s.steps, ","); got != "delta_login_review,change_password" {
+		t.Fatalf("got steps %s", got)
+	}
+	if insta.Account == nil {
+		t.Fatal("challenge did not log in")
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	srv := newServer(t)
+	srv.RequireTwoFactor("alice", TwoFactor{Code: "424242"})
+	insta, err := srv.Client("alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = insta.Login()
+	var tfErr goinsta.TwoFactorError
+	if !errors.Is(err, goinsta.ErrTwoFactorRequired) || !errors.As(err, &tfErr) {
+		t.Fatalf("got %v, want ErrTwoFactorRequired", err)
+	}
+	if tfErr.Info.TwoFactorIdentifier == "" || len(tfErr.Info.Methods()) != 1 {
+		t.Fatalf("got two factor info %+v", tfErr.Info)
+	}
+	if err := insta.ResendTwoFactorSMS(); err != nil {
+		t.Fatal(err)
+	}
+	if srv.TwoFactorCodesSent("alice") != 2 {
+		t.Fatalf("got %d codes sent", srv.TwoFactorCodesSent("alice"))
+	}
+
+	if err := insta.TwoFactorLogin("000000"); err == nil {
+		t.Fatal("wrong code accepted")
+	}
+	if err := insta.TwoFactorLogin("424 242"); err != nil {
+		t.Fatal(err)
+	}
+	if insta.Account == nil || insta.Account.Username != "alice" {
+		t.Fatal("account not set after the two factor login")
+	}
+	if err := insta.TwoFactorLogin("424242"); !errors.Is(err, goinsta.ErrNoTwoFactor) {
+		t.Fatalf("got %v without a pending login, want ErrNoTwoFactor", err)
+	}
+}
+
+func TestTwoFactorTOTP(t *testing.T) {
+	srv := newServer(t)
+	secret := "JBSWY3DPEHPK3PXP"
+	srv.RequireTwoFactor("alice", TwoFactor{Secret: secret})
+	insta, err := srv.Client("alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := insta.Login(); !errors.Is(err, goinsta.ErrTwoFactorRequired) {
+		t.Fatalf("got %v, want ErrTwoFactorRequired", err)
+	}
+	if srv.TwoFactorCodesSent("alice") != 0 {
+		t.Fatal("SMS code sent to an authenticator account")
+	}
+	if err := insta.TwoFactorLoginTOTP(secret); err != nil {
+		t.Fatal(err)
+	}
+	if insta.Account == nil || insta.Account.Username != "alice" {
+		t.Fatal("account not set after the two factor login")
+	}
+}
+
+func TestInject(t *testing.T) {
+	srv := newServer(t)
+	bob, _ := srv.User("bob")
+	srv.AddMedia(bob.ID, "")
+	insta := login(t, srv, "alice")
+	user, err := insta.Profiles.ByName("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := user.Feed()
+	feed.Next()
+	item := &feed.Items[0]
+
+	srv.Inject(Fault{
+		Method:   "POST",
+		Endpoint: "media/*/like/",
+		Message:  "feedback_required",
+		Times:    1,
+	})
+	if err := item.Like(); !errors.Is(err, goinsta.ErrFeedbackRequired) {
+		t.Fatalf("got %v, want ErrFeedbackRequired", err)
+	}
+	if err := item.Like(); err != nil {
+		t.Fatalf("fault not consumed: %v", err)
+	}
+
+	srv.Inject(Fault{
+		Endpoint:   "users/*/usernameinfo/",
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: 2 * time.Second,
+	})
+	_, err = insta.Profiles.ByName("bob")
+	var apiErr *goinsta.APIError
+	if !errors.Is(err, goinsta.ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != 2*time.Second {
+		t.Fatalf("got %v, want ErrRateLimited retrying after 2s", err)
+	}
+	srv.ClearFaults()
+	if _, err := insta.Profiles.ByName("bob"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -62,16 +62,21 @@ func (inst *Instagram) NewHashtag(name string) *Hashtag {
 
 // Sync updates Hashtag information preparing it to Next call.
 func (h *Hashtag) Sync() error {
+	return h.SyncContext(context.Background())
+}
+
+// SyncContext is the context-aware version of Sync.
+func (h *Hashtag) SyncContext(ctx context.Context) error {
 	insta := h.inst
 
-	body, err := insta.sendSimpleRequest(urlTagSync, h.Name)
+	body, err := insta.sendSimpleRequest(ctx, urlTagSync, h.Name)
 	if err == nil {
 		var resp struct {
 			Name       string `json:"name"`
 			ID         int64  `json:"id"`
 			MediaCount int    `json:"media_count"`
 		}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
 			h.Name = resp.Name
 			h.ID = resp.ID
@@ -84,16 +89,21 @@ func (h *Hashtag) Sync() error {
 
 // Next paginates over hashtag pages (xd).
 func (h *Hashtag) Next() bool {
+	return h.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (h *Hashtag) NextContext(ctx context.Context) bool {
 	if h.err != nil {
 		return false
 	}
 	insta := h.inst
 	name := h.Name
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Query: map[string]string{
 				"max_id":     h.NextID,
-				"rank_token": insta.rankToken,
+				"rank_token": insta.rankToken.Get(),
 				"page":       fmt.Sprintf("%d", h.NextPage),
 			},
 			Endpoint: fmt.Sprintf(urlTagContent, name),
@@ -102,7 +112,7 @@ func (h *Hashtag) Next() bool {
 	)
 	if err == nil {
 		ht := &Hashtag{}
-		err = json.Unmarshal(body, ht)
+		err = insta.unmarshal(body, ht)
 		if err == nil {
 			*h = *ht
 			h.inst = insta
@@ -125,7 +135,12 @@ func (h *Hashtag) Error() error {
 
 // Stories returns hashtag stories.
 func (h *Hashtag) Stories() (*StoryMedia, error) {
-	body, err := h.inst.sendSimpleRequest(
+	return h.StoriesContext(context.Background())
+}
+
+// StoriesContext is the context-aware version of Stories.
+func (h *Hashtag) StoriesContext(ctx context.Context) (*StoryMedia, error) {
+	body, err := h.inst.sendSimpleRequest(ctx,
 		urlTagStories, h.Name,
 	)
 	if err == nil {
@@ -133,7 +148,7 @@ func (h *Hashtag) Stories() (*StoryMedia, error) {
 			Story  StoryMedia `json:"story"`
 			Status string     `json:"status"`
 		}
-		err = json.Unmarshal(body, &resp)
+		err = h.inst.unmarshal(body, &resp)
 		return &resp.Story, err
 	}
 	return nil, err
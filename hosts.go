@@ -0,0 +1,81 @@
+package goinsta
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+)
+
+// Hosts are the base URLs used to reach instagram's API.
+//
+// A slash is appended to the fields that lack one. Empty fields fall back
+// to the instagram defaults, see DefaultHosts.
+type Hosts struct {
+	// API is the base URL of v1 endpoints.
+	API string `json:"api"`
+	// APIv2 is the base URL of v2 endpoints.
+	APIv2 string `json:"api_v2"`
+	// Upload is the base URL of v1 upload endpoints, like API on the
+	// upload host.
+	Upload string `json:"upload"`
+}
+
+// DefaultHosts returns the hosts used by the instagram application.
+func DefaultHosts() Hosts {
+	return Hosts{
+		API:    goInstaAPIUrl,
+		APIv2:  goInstaAPIUrlv2,
+		Upload: goInstaUploadURL,
+	}
+}
+
+// HostsFromBaseURL returns Hosts laid out like i.instagram.com below base,
+// e.g. http://127.0.0.1:8080/ serves v1 endpoints at http://127.0.0.1:8080/api/v1/.
+//
+// It is useful to point goinsta to a local server or a recording proxy.
+func HostsFromBaseURL(base string) (Hosts, error) {
+	u, err := neturl.Parse(base)
+	if err != nil {
+		return Hosts{}, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return Hosts{}, fmt.Errorf("invalid base url: %s", base)
+	}
+	base = withSlash(base)
+	return Hosts{
+		API:    base + "api/v1/",
+		APIv2:  base + "api/v2/",
+		Upload: base + "api/v1/",
+	}, nil
+}
+
+func (h Hosts) withDefaults() Hosts {
+	def := DefaultHosts()
+	if h.API == "" {
+		h.API = def.API
+	}
+	if h.APIv2 == "" {
+		h.APIv2 = def.APIv2
+	}
+	if h.Upload == "" {
+		h.Upload = def.Upload
+	}
+	h.API = withSlash(h.API)
+	h.APIv2 = withSlash(h.APIv2)
+	h.Upload = withSlash(h.Upload)
+	return h
+}
+
+// withSlash appends a slash to url if it has none, so that endpoints can
+// be appended to it.
+func withSlash(url string) string {
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	return url
+}
+
+// apiURL returns the parsed v1 host. It is where instagram sets its cookies.
+func (h Hosts) apiURL() (*neturl.URL, error) {
+	return neturl.Parse(h.API)
+}
@@ -0,0 +1,81 @@
+package goinsta
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetHosts(t *testing.T) {
+	insta := New("user", "pass")
+	insta.SetHosts(Hosts{API: "http://127.0.0.1:1/api/v1", Upload: "http://127.0.0.1:2/api/v1"})
+	want := Hosts{
+		API:    "http://127.0.0.1:1/api/v1/",
+		APIv2:  goInstaAPIUrlv2,
+		Upload: "http://127.0.0.1:2/api/v1/",
+	}
+	if got := insta.Hosts(); got != want {
+		t.Fatalf("got hosts %+v, want %+v", got, want)
+	}
+
+	hosts, err := HostsFromBaseURL("http://127.0.0.1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = Hosts{
+		API:    "http://127.0.0.1:1/api/v1/",
+		APIv2:  "http://127.0.0.1:1/api/v2/",
+		Upload: "http://127.0.0.1:1/api/v1/",
+	}
+	if hosts != want {
+		t.Fatalf("got hosts %+v, want %+v", hosts, want)
+	}
+	if _, err := HostsFromBaseURL("127.0.0.1:1"); err == nil {
+		t.Fatal("base url without scheme accepted")
+	}
+}
+
+func TestUploadHost(t *testing.T) {
+	var uploads []string
+	upload := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads = append(uploads, r.URL.Path)
+		w.Write([]byte(`{"status":"ok","upload_id":"1"}`))
+	}))
+	defer upload.Close()
+
+	var calls []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		w.Write([]byte(`{"status":"ok","media":{"id":"1_2","caption":{"text":"caption"}}}`))
+	}))
+	defer api.Close()
+
+	hosts, err := HostsFromBaseURL(api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts.Upload = upload.URL + "/api/v1"
+	insta, err := NewWithOptions("user", "pass", WithHosts(hosts))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var photo bytes.Buffer
+	png.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 4, 3)))
+	item, err := insta.UploadPhoto(&photo, "caption", 87, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "1_2" {
+		t.Fatalf("got item %q, want the configured media", item.ID)
+	}
+	if len(uploads) != 1 || uploads[0] != "/api/v1/"+urlUploadPhoto {
+		t.Fatalf("upload host received %v", uploads)
+	}
+	if len(calls) != 1 || calls[0] != "/api/v1/media/configure/" {
+		t.Fatalf("api host received %v", calls)
+	}
+}
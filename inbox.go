@@ -1,6 +1,7 @@
 package goinsta
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -72,14 +73,14 @@ func newInbox(inst *Instagram) *Inbox {
 	return &Inbox{inst: inst}
 }
 
-func (inbox *Inbox) sync(pending bool, params map[string]string) error {
+func (inbox *Inbox) sync(ctx context.Context, pending bool, params map[string]string) error {
 	endpoint := urlInbox
 	if pending {
 		endpoint = urlInboxPending
 	}
 
 	insta := inbox.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: endpoint,
 			Query:    params,
@@ -88,7 +89,7 @@ func (inbox *Inbox) sync(pending bool, params map[string]string) error {
 
 	if err == nil {
 		resp := inboxResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
 			*inbox = resp.Inbox
 			inbox.inst = insta
@@ -104,7 +105,7 @@ func (inbox *Inbox) sync(pending bool, params map[string]string) error {
 	return err
 }
 
-func (inbox *Inbox) next(pending bool, params map[string]string) bool {
+func (inbox *Inbox) next(ctx context.Context, pending bool, params map[string]string) bool {
 	endpoint := urlInbox
 	if pending {
 		endpoint = urlInboxPending
@@ -113,7 +114,7 @@ func (inbox *Inbox) next(pending bool, params map[string]string) bool {
 		return false
 	}
 	insta := inbox.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: endpoint,
 			Query:    params,
@@ -121,7 +122,7 @@ func (inbox *Inbox) next(pending bool, params map[string]string) bool {
 	)
 	if err == nil {
 		resp := inboxResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
 			*inbox = resp.Inbox
 			inbox.inst = insta
@@ -146,7 +147,12 @@ func (inbox *Inbox) next(pending bool, params map[string]string) bool {
 //
 // See example: examples/inbox/sync.go
 func (inbox *Inbox) Sync() error {
-	return inbox.sync(false, map[string]string{
+	return inbox.SyncContext(context.Background())
+}
+
+// SyncContext is the context-aware version of Sync.
+func (inbox *Inbox) SyncContext(ctx context.Context) error {
+	return inbox.sync(ctx, false, map[string]string{
 		"persistentBadging": "true",
 		"use_unified_inbox": "true",
 	})
@@ -156,13 +162,23 @@ func (inbox *Inbox) Sync() error {
 //
 // See example: examples/inbox/sync.go
 func (inbox *Inbox) SyncPending() error {
-	return inbox.sync(true, map[string]string{})
+	return inbox.SyncPendingContext(context.Background())
+}
+
+// SyncPendingContext is the context-aware version of SyncPending.
+func (inbox *Inbox) SyncPendingContext(ctx context.Context) error {
+	return inbox.sync(ctx, true, map[string]string{})
 }
 
 // New initialises a new conversation with a user, for further messages you should use Conversation.Send
 //
 // See example: examples/inbox/newconversation.go
 func (inbox *Inbox) New(user *User, text string) error {
+	return inbox.NewContext(context.Background(), user, text)
+}
+
+// NewContext is the context-aware version of New.
+func (inbox *Inbox) NewContext(ctx context.Context, user *User, text string) error {
 	insta := inbox.inst
 	to, err := prepareRecipients(user.ID)
 	if err != nil {
@@ -178,7 +194,7 @@ func (inbox *Inbox) New(user *User, text string) error {
 			"text":            text,
 		},
 	)
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Connection: "keep-alive",
 			Endpoint:   urlInboxSend,
@@ -198,7 +214,12 @@ func (inbox *Inbox) Reset() {
 //
 // See example: examples/inbox/next.go
 func (inbox *Inbox) Next() bool {
-	return inbox.next(false, map[string]string{
+	return inbox.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (inbox *Inbox) NextContext(ctx context.Context) bool {
+	return inbox.next(ctx, false, map[string]string{
 		"persistentBadging": "true",
 		"use_unified_inbox": "true",
 		"cursor":            inbox.Cursor,
@@ -209,7 +230,12 @@ func (inbox *Inbox) Next() bool {
 //
 // See example: examples/inbox/next.go
 func (inbox *Inbox) NextPending() bool {
-	return inbox.next(true, map[string]string{
+	return inbox.NextPendingContext(context.Background())
+}
+
+// NextPendingContext is the context-aware version of NextPending.
+func (inbox *Inbox) NextPendingContext(ctx context.Context) bool {
+	return inbox.next(ctx, true, map[string]string{
 		"cursor": inbox.Cursor,
 	})
 }
@@ -269,6 +295,11 @@ func (c Conversation) lastItemID() string {
 //
 // See example: examples/media/likeAll.go
 func (c *Conversation) Like() error {
+	return c.LikeContext(context.Background())
+}
+
+// LikeContext is the context-aware version of Like.
+func (c *Conversation) LikeContext(ctx context.Context) error {
 	insta := c.inst
 	to, err := prepareRecipients(c)
 	if err != nil {
@@ -288,7 +319,7 @@ func (c *Conversation) Like() error {
 			"action":          "send_item",
 		},
 	)
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Connection: "keep-alive",
 			Endpoint:   urlInboxSendLike,
@@ -303,6 +334,11 @@ func (c *Conversation) Like() error {
 //
 // See example: examples/inbox/sms.go
 func (c *Conversation) Send(text string) error {
+	return c.SendContext(context.Background(), text)
+}
+
+// SendContext is the context-aware version of Send.
+func (c *Conversation) SendContext(ctx context.Context, text string) error {
 	insta := c.inst
 	// I DON'T KNOW WHY BUT INSTAGRAM WANTS A DOUBLE SLICE OF INTS FOR ONE ID.
 	to, err := prepareRecipients(c)
@@ -325,7 +361,7 @@ func (c *Conversation) Send(text string) error {
 			"text":            text,
 		},
 	)
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Connection: "keep-alive",
 			Endpoint:   urlInboxSend,
@@ -346,6 +382,11 @@ func (c *Conversation) Write(b []byte) (int, error) {
 //
 // See example: examples/inbox/conversation.go
 func (c *Conversation) Next() bool {
+	return c.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (c *Conversation) NextContext(ctx context.Context) bool {
 	if c.err != nil {
 		return false
 	}
@@ -355,7 +396,7 @@ func (c *Conversation) Next() bool {
 	}
 
 	insta := c.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlInboxThread, c.ID),
 			Query: map[string]string{
@@ -367,7 +408,7 @@ func (c *Conversation) Next() bool {
 	)
 	if err == nil {
 		resp := threadResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
 			*c = resp.Conversation
 			c.inst = insta
@@ -0,0 +1,74 @@
+package goinsta
+
+import (
+	"context"
+)
+
+// Call is a logical API call, as sent by goinsta.
+//
+// Interceptors can change the request fields before the call is sent.
+type Call struct {
+	// Endpoint is the request path, relative to the API host.
+	Endpoint string
+	// Method is GET or POST.
+	Method string
+	// Query are the request parameters. They are sent in the URL
+	// for GET requests and as form body for POST requests.
+	Query map[string]string
+	// UseV2 is set when Endpoint is relative to the v2 API host.
+	UseV2 bool
+
+	// StatusCode is the HTTP status code of the response.
+	// It is 0 until a response has been received.
+	StatusCode int
+	// Body is the response body.
+	Body []byte
+
+	// viewer is the id of the account sending the call, 0 before login
+	viewer int64
+}
+
+// StatusError returns the error goinsta reports for the call status code and body.
+// It is nil for successful calls.
+//
+// Interceptors answering calls by themselves can use it to return the
+// same errors as instagram would produce.
+func (call *Call) StatusError() error {
+	return isError(call.Endpoint, call.StatusCode, call.Body)
+}
+
+// Handler sends a call.
+type Handler func(ctx context.Context, call *Call) error
+
+// Interceptor wraps every API call.
+//
+// An interceptor can change call before calling next, answer the call by itself
+// (setting StatusCode and Body without calling next) or observe the
+// result of next. The error returned by the interceptor is the one
+// returned to the caller of the API method.
+//
+// Retries and rate limiting happen inside next. Photo uploads are not
+// sent through interceptors.
+type Interceptor func(ctx context.Context, call *Call, next Handler) error
+
+// Use appends interceptors to the chain wrapping API calls.
+// The first interceptor is the outermost one.
+func (inst *Instagram) Use(interceptors ...Interceptor) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	// never append in place, chain may be iterating over the previous slice
+	inst.interceptors = append(inst.interceptors[:len(inst.interceptors):len(inst.interceptors)], interceptors...)
+}
+
+func (inst *Instagram) chain(h Handler) Handler {
+	inst.mu.RLock()
+	interceptors := inst.interceptors
+	inst.mu.RUnlock()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], h
+		h = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return h
+}
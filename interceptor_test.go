@@ -0,0 +1,63 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("max_id")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta := New("user", "pass")
+	if err := insta.SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	var status int
+	insta.Use(
+		func(ctx context.Context, call *Call, next Handler) error {
+			order = append(order, "outer")
+			err := next(ctx, call)
+			status = call.StatusCode
+			return err
+		},
+		func(ctx context.Context, call *Call, next Handler) error {
+			order = append(order, "inner")
+			call.Query = map[string]string{"max_id": "42"}
+			return next(ctx, call)
+		},
+	)
+
+	body, err := insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"status":"ok"}` || got != "42" || status != http.StatusOK {
+		t.Fatalf("got body %s, max_id %q and status %d", body, got, status)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Fatalf("interceptors called in order %v", order)
+	}
+
+	// short-circuited calls never reach the server
+	got = ""
+	insta.interceptors = nil
+	insta.Use(func(ctx context.Context, call *Call, next Handler) error {
+		call.StatusCode = http.StatusNotFound
+		call.Body = []byte(`{"status":"fail","message":"not found"}`)
+		return call.StatusError()
+	})
+	_, err = insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline})
+	if !errors.Is(err, ErrNotFound) || got != "" {
+		t.Fatalf("got %v, want injected ErrNotFound", err)
+	}
+}
@@ -0,0 +1,183 @@
+package goinsta
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Category groups the endpoints sharing the same rate budget.
+type Category string
+
+// Request categories used by Limiter.
+const (
+	CategoryRead    Category = "read"
+	CategoryLike    Category = "like"
+	CategoryFollow  Category = "follow"
+	CategoryComment Category = "comment"
+	CategoryDirect  Category = "direct"
+	CategoryUpload  Category = "upload"
+)
+
+// Limiter paces the requests sent to instagram.
+//
+// Wait is called before every request (including retries).
+type Limiter interface {
+	// Wait blocks until a request of category c can be sent or ctx is done.
+	Wait(ctx context.Context, c Category) error
+}
+
+// SetLimiter sets the limiter consulted before every request.
+// A nil limiter disables rate limiting.
+func (inst *Instagram) SetLimiter(l Limiter) {
+	inst.mu.Lock()
+	inst.limiter = l
+	inst.mu.Unlock()
+}
+
+func (inst *Instagram) wait(ctx context.Context, c Category) error {
+	inst.mu.RLock()
+	l := inst.limiter
+	inst.mu.RUnlock()
+	if l == nil {
+		return nil
+	}
+	return l.Wait(ctx, c)
+}
+
+// categoryOf returns the category of endpoint.
+func categoryOf(endpoint string) Category {
+	switch {
+	case strings.Contains(endpoint, "upload/"),
+		strings.HasPrefix(endpoint, "rupload_"),
+		strings.HasPrefix(endpoint, "media/configure"):
+		return CategoryUpload
+	case strings.HasPrefix(endpoint, "direct_v2/threads/broadcast/"):
+		return CategoryDirect
+	case strings.HasSuffix(endpoint, "/like/"),
+		strings.HasSuffix(endpoint, "/unlike/"),
+		strings.HasSuffix(endpoint, "/comment_like/"),
+		strings.HasSuffix(endpoint, "/comment_unlike/"):
+		return CategoryLike
+	case strings.Contains(endpoint, "/comment/"):
+		return CategoryComment
+	case strings.HasPrefix(endpoint, "friendships/create/"),
+		strings.HasPrefix(endpoint, "friendships/destroy/"),
+		strings.HasPrefix(endpoint, "friendships/block/"),
+		strings.HasPrefix(endpoint, "friendships/unblock/"):
+		return CategoryFollow
+	}
+	return CategoryRead
+}
+
+// Budget is the rate allowed to a category.
+type Budget struct {
+	// Requests is the number of requests allowed every Per.
+	Requests int
+	Per      time.Duration
+	// Burst is the maximum number of requests that can be sent in a row.
+	Burst int
+}
+
+// DefaultBudgets returns conservative budgets for every category.
+func DefaultBudgets() map[Category]Budget {
+	return map[Category]Budget{
+		CategoryRead:    {Requests: 600, Per: time.Hour, Burst: 30},
+		CategoryLike:    {Requests: 60, Per: time.Hour, Burst: 5},
+		CategoryFollow:  {Requests: 30, Per: time.Hour, Burst: 3},
+		CategoryComment: {Requests: 30, Per: time.Hour, Burst: 3},
+		CategoryDirect:  {Requests: 30, Per: time.Hour, Burst: 3},
+		CategoryUpload:  {Requests: 10, Per: time.Hour, Burst: 2},
+	}
+}
+
+// BucketLimiter is a Limiter using one token bucket per category.
+//
+// Categories without budget are not limited. It is safe for concurrent use
+// and can be shared by many sessions.
+type BucketLimiter struct {
+	mu      sync.Mutex
+	buckets map[Category]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	burst  float64
+	// rate is the number of tokens added every second
+	rate float64
+	last time.Time
+}
+
+// NewBucketLimiter returns a limiter using budgets.
+// If budgets is nil DefaultBudgets is used.
+func NewBucketLimiter(budgets map[Category]Budget) *BucketLimiter {
+	if budgets == nil {
+		budgets = DefaultBudgets()
+	}
+	l := &BucketLimiter{
+		buckets: make(map[Category]*bucket),
+	}
+	now := time.Now()
+	for c, b := range budgets {
+		if b.Requests <= 0 || b.Per <= 0 {
+			continue
+		}
+		burst := float64(b.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		l.buckets[c] = &bucket{
+			tokens: burst,
+			burst:  burst,
+			rate:   float64(b.Requests) / b.Per.Seconds(),
+			last:   now,
+		}
+	}
+	return l
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// Wait blocks until a request of category c can be sent or ctx is done.
+func (l *BucketLimiter) Wait(ctx context.Context, c Category) error {
+	for {
+		l.mu.Lock()
+		b, ok := l.buckets[c]
+		if !ok {
+			l.mu.Unlock()
+			return nil
+		}
+		b.refill(time.Now())
+		if b.tokens >= 1 {
+			b.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Remaining returns the number of requests of category c that can be sent
+// right now. It returns -1 if c is not limited.
+func (l *BucketLimiter) Remaining(c Category) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[c]
+	if !ok {
+		return -1
+	}
+	b.refill(time.Now())
+	return int(b.tokens)
+}
@@ -0,0 +1,104 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCategoryOf(t *testing.T) {
+	for _, test := range []struct {
+		endpoint string
+		category Category
+	}{
+		{"feed/timeline/", CategoryRead},
+		{"users/1/info/", CategoryRead},
+		{"media/1_2/like/", CategoryLike},
+		{"media/1_2/unlike/", CategoryLike},
+		{"media/3/comment_like/", CategoryLike},
+		{"media/3/comment_unlike/", CategoryLike},
+		{"media/1_2/comment/", CategoryComment},
+		{"media/1_2/comment/bulk_delete/", CategoryComment},
+		{"friendships/create/1/", CategoryFollow},
+		{"friendships/destroy/1/", CategoryFollow},
+		{"friendships/block/1/", CategoryFollow},
+		{"friendships/unblock/1/", CategoryFollow},
+		{"friendships/show/1/", CategoryRead},
+		{"direct_v2/threads/broadcast/text/", CategoryDirect},
+		{"direct_v2/inbox/", CategoryRead},
+		{"upload/photo/", CategoryUpload},
+		{"rupload_igphoto/123", CategoryUpload},
+		{"media/configure/", CategoryUpload},
+		{"media/configure_to_story/", CategoryUpload},
+	} {
+		if c := categoryOf(test.endpoint); c != test.category {
+			t.Errorf("%s: got category %s, want %s", test.endpoint, c, test.category)
+		}
+	}
+}
+
+func TestBucketLimiter(t *testing.T) {
+	l := NewBucketLimiter(map[Category]Budget{
+		CategoryLike:   {Requests: 1, Per: time.Hour, Burst: 2},
+		CategoryFollow: {Requests: 100, Per: time.Second},
+	})
+	ctx := context.Background()
+
+	if l.Remaining(CategoryRead) != -1 {
+		t.Fatal("category without budget is limited")
+	}
+	if err := l.Wait(ctx, CategoryRead); err != nil {
+		t.Fatal(err)
+	}
+
+	// the burst is sent without waiting
+	if l.Remaining(CategoryLike) != 2 {
+		t.Fatalf("got %d remaining, want the burst", l.Remaining(CategoryLike))
+	}
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx, CategoryLike); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if l.Remaining(CategoryLike) != 0 {
+		t.Fatalf("got %d remaining after the burst", l.Remaining(CategoryLike))
+	}
+
+	// the next request waits for a token until ctx is done
+	wctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Wait(wctx, CategoryLike); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Wait returned %v after ctx was done", d)
+	}
+
+	// tokens are refilled with time, up to the burst
+	l.mu.Lock()
+	l.buckets[CategoryLike].last = time.Now().Add(-30 * time.Minute)
+	l.mu.Unlock()
+	if l.Remaining(CategoryLike) != 0 {
+		t.Fatalf("got %d remaining after half a token", l.Remaining(CategoryLike))
+	}
+	l.mu.Lock()
+	l.buckets[CategoryLike].last = time.Now().Add(-10 * time.Hour)
+	l.mu.Unlock()
+	if l.Remaining(CategoryLike) != 2 {
+		t.Fatalf("got %d remaining, want the burst", l.Remaining(CategoryLike))
+	}
+
+	// without burst, requests wait for the next token
+	if err := l.Wait(ctx, CategoryFollow); err != nil {
+		t.Fatal(err)
+	}
+	start = time.Now()
+	if err := l.Wait(ctx, CategoryFollow); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 5*time.Millisecond {
+		t.Fatalf("second request sent after %v, want about 10ms", d)
+	}
+}
@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -39,18 +39,24 @@ type Section struct {
 }
 
 func (l *LocationInstance) Feeds(locationID int64) (*Section, error) {
+	return l.FeedsContext(context.Background(), locationID)
+}
+
+// FeedsContext is the context-aware version of Feeds.
+func (l *LocationInstance) FeedsContext(ctx context.Context, locationID int64) (*Section, error) {
 	// TODO: use pagination for location feeds.
 	insta := l.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlFeedLocations, locationID),
 			Query: map[string]string{
-				"rank_token":     insta.rankToken,
+				"rank_token":     insta.rankToken.Get(),
 				"ranked_content": "true",
-				"_csrftoken":     insta.token,
-				"_uuid":          insta.uuid,
+				"_csrftoken":     insta.token.Get(),
+				"_uuid":          insta.uuid.Get(),
 			},
-			IsPost: true,
+			IsPost:     true,
+			Idempotent: true,
 		},
 	)
 	if err != nil {
@@ -58,6 +64,6 @@ func (l *LocationInstance) Feeds(locationID int64) (*Section, error) {
 	}
 
 	section := &Section{}
-	err = json.Unmarshal(body, section)
+	err = insta.unmarshal(body, section)
 	return section, err
 }
@@ -0,0 +1,148 @@
+package goinsta
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Logger receives leveled log entries from goinsta.
+//
+// keyvals are alternating keys and values, as in
+// Debug("request", "endpoint", "feed/timeline/", "method", "GET").
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// SetLogger sets the logger used by the session. nil disables logging.
+//
+// Passwords, tokens, cookies and signed bodies are redacted before they
+// reach l.
+func (inst *Instagram) SetLogger(l Logger) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if l == nil {
+		inst.log = nil
+		return
+	}
+	inst.log = redactLogger{l}
+}
+
+func (inst *Instagram) logger() Logger {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.log == nil {
+		return nopLogger{}
+	}
+	return inst.log
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, keyvals ...interface{}) {}
+func (nopLogger) Info(msg string, keyvals ...interface{})  {}
+func (nopLogger) Warn(msg string, keyvals ...interface{})  {}
+func (nopLogger) Error(msg string, keyvals ...interface{}) {}
+
+// redacted replaces sensitive values in log entries.
+const redacted = "[redacted]"
+
+// sensitiveKeys are the keys whose values are never logged.
+var sensitiveKeys = map[string]bool{
+	"password":     true,
+	"enc_password": true,
+	"_csrftoken":   true,
+	"csrftoken":    true,
+	"token":        true,
+	"cookie":       true,
+	"cookies":      true,
+	"sessionid":    true,
+	"signed_body":  true,
+}
+
+type redactLogger struct {
+	l Logger
+}
+
+func (r redactLogger) Debug(msg string, keyvals ...interface{}) { r.l.Debug(msg, redact(keyvals)...) }
+func (r redactLogger) Info(msg string, keyvals ...interface{})  { r.l.Info(msg, redact(keyvals)...) }
+func (r redactLogger) Warn(msg string, keyvals ...interface{})  { r.l.Warn(msg, redact(keyvals)...) }
+func (r redactLogger) Error(msg string, keyvals ...interface{}) { r.l.Error(msg, redact(keyvals)...) }
+
+// redact returns a copy of keyvals with sensitive values replaced.
+func redact(keyvals []interface{}) []interface{} {
+	out := make([]interface{}, len(keyvals))
+	for i := range keyvals {
+		if i%2 == 1 && sensitiveKeys[strings.ToLower(fmt.Sprint(keyvals[i-1]))] {
+			out[i] = redacted
+			continue
+		}
+		out[i] = redactValue(keyvals[i])
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]string:
+		m := make(map[string]string, len(v))
+		for k, s := range v {
+			if sensitiveKeys[strings.ToLower(k)] {
+				s = redacted
+			}
+			m[k] = s
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			if sensitiveKeys[strings.ToLower(k)] {
+				s = redacted
+			}
+			m[k] = s
+		}
+		return m
+	case *http.Cookie, []*http.Cookie:
+		return redacted
+	}
+	return v
+}
+
+// StdLogger adapts a standard library logger. Debug entries are dropped
+// unless debug is set.
+func StdLogger(l *log.Logger, debug bool) Logger {
+	return stdLogger{l: l, debug: debug}
+}
+
+type stdLogger struct {
+	l     *log.Logger
+	debug bool
+}
+
+func (s stdLogger) Debug(msg string, keyvals ...interface{}) {
+	if s.debug {
+		s.print("DEBUG", msg, keyvals)
+	}
+}
+func (s stdLogger) Info(msg string, keyvals ...interface{})  { s.print("INFO", msg, keyvals) }
+func (s stdLogger) Warn(msg string, keyvals ...interface{})  { s.print("WARN", msg, keyvals) }
+func (s stdLogger) Error(msg string, keyvals ...interface{}) { s.print("ERROR", msg, keyvals) }
+
+func (s stdLogger) print(level, msg string, keyvals []interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		var v interface{} = "(missing)"
+		if i+1 < len(keyvals) {
+			v = keyvals[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%v", keyvals[i], v)
+	}
+	s.l.Print(b.String())
+}
@@ -0,0 +1,27 @@
+package goinsta
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := redactLogger{StdLogger(log.New(&buf, "", 0), true)}
+
+	l.Debug("request",
+		"token", "secret-token",
+		"query", map[string]string{"signed_body": "secret-body", "max_id": "42"},
+		"cookies", []*http.Cookie{{Name: "sessionid", Value: "secret-session"}},
+	)
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Fatalf("sensitive value logged: %s", out)
+	}
+	if !strings.Contains(out, "DEBUG request") || !strings.Contains(out, "max_id:42") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+}
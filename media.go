@@ -2,10 +2,10 @@ package goinsta
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	neturl "net/url"
@@ -174,9 +174,14 @@ type Item struct {
 // If parent media is a Story this function will send a private message
 // replying the Instagram story.
 func (item *Item) Comment(text string) error {
+	return item.CommentContext(context.Background(), text)
+}
+
+// CommentContext is the context-aware version of Comment.
+func (item *Item) CommentContext(ctx context.Context, text string) error {
 	var opt *reqOptions
 	var err error
-	insta := item.media.instagram()
+	insta := item.media.Instagram()
 
 	switch item.media.(type) {
 	case *StoryMedia:
@@ -211,7 +216,7 @@ func (item *Item) Comment(text string) error {
 		)
 		opt = &reqOptions{
 			Endpoint: fmt.Sprintf(urlCommentAdd, item.Pk),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		}
 	}
@@ -220,7 +225,7 @@ func (item *Item) Comment(text string) error {
 	}
 
 	// ignoring response
-	_, err = insta.sendRequest(opt)
+	_, err = insta.sendRequest(ctx, opt)
 	return err
 }
 
@@ -239,7 +244,7 @@ func (item *Item) MediaToString() string {
 
 func setToItem(item *Item, media Media) {
 	item.media = media
-	item.User.inst = media.instagram()
+	item.User.inst = media.Instagram()
 	item.Comments = newComments(item)
 	for i := range item.CarouselMedia {
 		item.CarouselMedia[i].User = item.User
@@ -266,19 +271,45 @@ func getname(name string) string {
 	return name
 }
 
-func download(inst *Instagram, url, dst string) (string, error) {
+func download(ctx context.Context, inst *Instagram, url, dst string) (string, error) {
 	file, err := os.Create(dst)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
-	resp, err := inst.c.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
+	stats := RequestStats{Endpoint: "download", Method: "GET"}
+	start := time.Now()
+	defer func() {
+		stats.Latency = time.Since(start)
+		inst.observe(stats)
+	}()
 
-	_, err = io.Copy(file, resp.Body)
+	resp, err := inst.client().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			stats.Err = ctx.Err()
+			return "", ctx.Err()
+		}
+		stats.Err = networkError{err}
+		return "", err
+	}
+	defer resp.Body.Close()
+	stats.StatusCode = resp.StatusCode
+
+	cr := &countingReader{r: contextReader{ctx, resp.Body}}
+	defer func() {
+		stats.BytesReceived = cr.n
+	}()
+	r, err := decodeBody(cr, resp.Header.Get("Content-Encoding"))
+	if err == nil {
+		_, err = io.Copy(file, r)
+	}
+	stats.Err = err
 	return dst, err
 }
 
@@ -349,7 +380,12 @@ func (item *Item) Hashtags() []Hashtag {
 //
 // See example: examples/media/mediaDelete.go
 func (item *Item) Delete() error {
-	insta := item.media.instagram()
+	return item.DeleteContext(context.Background())
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (item *Item) DeleteContext(ctx context.Context) error {
+	insta := item.media.Instagram()
 	data, err := insta.prepareData(
 		map[string]interface{}{
 			"media_id": item.ID,
@@ -359,10 +395,10 @@ func (item *Item) Delete() error {
 		return err
 	}
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlMediaDelete, item.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -373,13 +409,18 @@ func (item *Item) Delete() error {
 //
 // This function updates Item.Likers value
 func (item *Item) SyncLikers() error {
+	return item.SyncLikersContext(context.Background())
+}
+
+// SyncLikersContext is the context-aware version of SyncLikers.
+func (item *Item) SyncLikersContext(ctx context.Context) error {
 	resp := respLikers{}
-	insta := item.media.instagram()
-	body, err := insta.sendSimpleRequest(urlMediaLikers, item.ID)
+	insta := item.media.Instagram()
+	body, err := insta.sendSimpleRequest(ctx, urlMediaLikers, item.ID)
 	if err != nil {
 		return err
 	}
-	err = json.Unmarshal(body, &resp)
+	err = insta.unmarshal(body, &resp)
 	if err == nil {
 		item.Likers = resp.Users
 	}
@@ -390,7 +431,12 @@ func (item *Item) SyncLikers() error {
 //
 // See example: examples/media/unlike.go
 func (item *Item) Unlike() error {
-	insta := item.media.instagram()
+	return item.UnlikeContext(context.Background())
+}
+
+// UnlikeContext is the context-aware version of Unlike.
+func (item *Item) UnlikeContext(ctx context.Context) error {
+	insta := item.media.Instagram()
 	data, err := insta.prepareData(
 		map[string]interface{}{
 			"media_id": item.ID,
@@ -400,10 +446,10 @@ func (item *Item) Unlike() error {
 		return err
 	}
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlMediaUnlike, item.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -414,7 +460,12 @@ func (item *Item) Unlike() error {
 //
 // See example: examples/media/like.go
 func (item *Item) Like() error {
-	insta := item.media.instagram()
+	return item.LikeContext(context.Background())
+}
+
+// LikeContext is the context-aware version of Like.
+func (item *Item) LikeContext(ctx context.Context) error {
+	insta := item.media.Instagram()
 	data, err := insta.prepareData(
 		map[string]interface{}{
 			"media_id": item.ID,
@@ -424,10 +475,10 @@ func (item *Item) Like() error {
 		return err
 	}
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlMediaLike, item.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -438,7 +489,12 @@ func (item *Item) Like() error {
 //
 // You can get saved media using Account.Saved()
 func (item *Item) Save() error {
-	insta := item.media.instagram()
+	return item.SaveContext(context.Background())
+}
+
+// SaveContext is the context-aware version of Save.
+func (item *Item) SaveContext(ctx context.Context) error {
+	insta := item.media.Instagram()
 	data, err := insta.prepareData(
 		map[string]interface{}{
 			"media_id": item.ID,
@@ -448,10 +504,10 @@ func (item *Item) Save() error {
 		return err
 	}
 
-	_, err = insta.sendRequest(
+	_, err = insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlMediaSave, item.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -473,11 +529,16 @@ func (item *Item) Save() error {
 //
 // See example: examples/media/itemDownload.go
 func (item *Item) Download(folder, name string) (imgs, vds string, err error) {
+	return item.DownloadContext(context.Background(), folder, name)
+}
+
+// DownloadContext is the context-aware version of Download.
+func (item *Item) DownloadContext(ctx context.Context, folder, name string) (imgs, vds string, err error) {
 	var u *neturl.URL
 	var nname string
 	imgFolder := path.Join(folder, "images")
 	vidFolder := path.Join(folder, "videos")
-	inst := item.media.instagram()
+	inst := item.media.Instagram()
 
 	os.MkdirAll(folder, 0777)
 	os.MkdirAll(imgFolder, 0777)
@@ -497,7 +558,7 @@ func (item *Item) Download(folder, name string) (imgs, vds string, err error) {
 		}
 		nname = getname(nname)
 
-		vds, err = download(inst, vds, nname)
+		vds, err = download(ctx, inst, vds, nname)
 		return "", vds, err
 	}
 
@@ -515,7 +576,7 @@ func (item *Item) Download(folder, name string) (imgs, vds string, err error) {
 		}
 		nname = getname(nname)
 
-		imgs, err = download(inst, imgs, nname)
+		imgs, err = download(ctx, inst, imgs, nname)
 		return imgs, "", err
 	}
 
@@ -597,8 +658,8 @@ type Media interface {
 	ID() string
 	// Delete removes media
 	Delete() error
-
-	instagram() *Instagram
+	// Instagram returns the session the media belongs to
+	Instagram() *Instagram
 }
 
 //StoryMedia is the struct that handles the information from the methods to get info about Stories.
@@ -634,6 +695,11 @@ type StoryMedia struct {
 //
 // See example: examples/media/deleteStories.go
 func (media *StoryMedia) Delete() error {
+	return media.DeleteContext(context.Background())
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (media *StoryMedia) DeleteContext(ctx context.Context) error {
 	insta := media.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -641,10 +707,10 @@ func (media *StoryMedia) Delete() error {
 		},
 	)
 	if err == nil {
-		_, err = insta.sendRequest(
+		_, err = insta.sendRequest(ctx,
 			&reqOptions{
 				Endpoint: fmt.Sprintf(urlMediaDelete, media.ID()),
-				Query:    generateSignature(data),
+				Query:    insta.generateSignature(data),
 				IsPost:   true,
 			},
 		)
@@ -663,7 +729,8 @@ func (media *StoryMedia) ID() string {
 	return ""
 }
 
-func (media *StoryMedia) instagram() *Instagram {
+// Instagram returns the session of the story.
+func (media *StoryMedia) Instagram() *Instagram {
 	return media.inst
 }
 
@@ -694,10 +761,10 @@ func (media *StoryMedia) Seen() error {
 		},
 	)
 	if err == nil {
-		_, err = insta.sendRequest(
+		_, err = insta.sendRequest(ctx,
 			&reqOptions{
 				Endpoint: urlMediaSeen, // reel=1&live_vod=0
-				Query:    generateSignature(data),
+				Query:    insta.generateSignature(data),
 				IsPost:   true,
 				UseV2:    true,
 			},
@@ -719,6 +786,11 @@ type trayRequest struct {
 //
 // This function updates StoryMedia.Items
 func (media *StoryMedia) Sync() error {
+	return media.SyncContext(context.Background())
+}
+
+// SyncContext is the context-aware version of Sync.
+func (media *StoryMedia) SyncContext(ctx context.Context) error {
 	insta := media.inst
 	query := []trayRequest{
 		{"SUPPORTED_SDK_VERSIONS", "9.0,10.0,11.0,12.0,13.0,14.0,15.0,16.0,17.0,18.0,19.0,20.0,21.0,22.0,23.0,24.0"},
@@ -742,16 +814,17 @@ func (media *StoryMedia) Sync() error {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
-			Endpoint: urlReelMedia,
-			Query:    generateSignature(data),
-			IsPost:   true,
+			Endpoint:   urlReelMedia,
+			Query:      insta.generateSignature(data),
+			IsPost:     true,
+			Idempotent: true,
 		},
 	)
 	if err == nil {
 		resp := trayResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
 			m, ok := resp.Reels[id]
 			if ok {
@@ -772,6 +845,11 @@ func (media *StoryMedia) Sync() error {
 // returns false when list reach the end
 // if StoryMedia.Error() is ErrNoMore no problem have been occurred.
 func (media *StoryMedia) Next(params ...interface{}) bool {
+	return media.NextContext(context.Background(), params...)
+}
+
+// NextContext is the context-aware version of Next.
+func (media *StoryMedia) NextContext(ctx context.Context, params ...interface{}) bool {
 	if media.err != nil {
 		return false
 	}
@@ -782,10 +860,10 @@ func (media *StoryMedia) Next(params ...interface{}) bool {
 		endpoint = fmt.Sprintf(endpoint, media.uid)
 	}
 
-	body, err := insta.sendSimpleRequest(endpoint)
+	body, err := insta.sendSimpleRequest(ctx, endpoint)
 	if err == nil {
 		m := StoryMedia{}
-		err = json.Unmarshal(body, &m)
+		err = insta.unmarshal(body, &m)
 		if err == nil {
 			// TODO check NextID media
 			*media = m
@@ -1032,13 +1110,19 @@ type FeedMedia struct {
 //
 // See example: examples/media/mediaDelete.go
 func (media *FeedMedia) Delete() error {
+	return media.DeleteContext(context.Background())
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (media *FeedMedia) DeleteContext(ctx context.Context) error {
 	for i := range media.Items {
-		media.Items[i].Delete()
+		media.Items[i].DeleteContext(ctx)
 	}
 	return nil
 }
 
-func (media *FeedMedia) instagram() *Instagram {
+// Instagram returns the session of the media.
+func (media *FeedMedia) Instagram() *Instagram {
 	return media.inst
 }
 
@@ -1055,6 +1139,11 @@ func (media *FeedMedia) SetID(id interface{}) {
 
 // Sync updates media values.
 func (media *FeedMedia) Sync() error {
+	return media.SyncContext(context.Background())
+}
+
+// SyncContext is the context-aware version of Sync.
+func (media *FeedMedia) SyncContext(ctx context.Context) error {
 	id := media.ID()
 	insta := media.inst
 
@@ -1067,10 +1156,10 @@ func (media *FeedMedia) Sync() error {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlMediaInfo, id),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   false,
 		},
 	)
@@ -1079,7 +1168,7 @@ func (media *FeedMedia) Sync() error {
 	}
 
 	m := FeedMedia{}
-	err = json.Unmarshal(body, &m)
+	err = insta.unmarshal(body, &m)
 	*media = m
 	media.endpoint = urlMediaInfo
 	media.inst = insta
@@ -1117,6 +1206,11 @@ func (media *FeedMedia) ID() string {
 // returns false when list reach the end.
 // if FeedMedia.Error() is ErrNoMore no problem have been occurred.
 func (media *FeedMedia) Next(params ...interface{}) bool {
+	return media.NextContext(context.Background(), params...)
+}
+
+// NextContext is the context-aware version of Next.
+func (media *FeedMedia) NextContext(ctx context.Context, params ...interface{}) bool {
 	if media.err != nil {
 		return false
 	}
@@ -1142,16 +1236,17 @@ func (media *FeedMedia) Next(params ...interface{}) bool {
 			}
 		}
 	}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: endpoint,
 			Query: map[string]string{
 				"max_id":         next,
-				"rank_token":     insta.rankToken,
+				"rank_token":     insta.rankToken.Get(),
 				"min_timestamp":  media.timestamp,
 				"ranked_content": ranked,
 			},
-			IsPost: media.IsTimelineMedia,
+			IsPost:     media.IsTimelineMedia,
+			Idempotent: true,
 		},
 	)
 	if err == nil {
@@ -1159,6 +1254,7 @@ func (media *FeedMedia) Next(params ...interface{}) bool {
 		d := json.NewDecoder(bytes.NewReader(body))
 		d.UseNumber()
 		err = d.Decode(&m)
+		insta.reportDrift(body, &m)
 		if err == nil {
 			*media = m
 			media.inst = insta
@@ -1170,14 +1266,20 @@ func (media *FeedMedia) Next(params ...interface{}) bool {
 			return true
 		}
 	}
+	media.err = err
 	return false
 }
 
 // UploadPhoto post image from io.Reader to instagram.
 func (insta *Instagram) UploadPhoto(photo io.Reader, photoCaption string, quality int, filterType int) (Item, error) {
+	return insta.UploadPhotoContext(context.Background(), photo, photoCaption, quality, filterType)
+}
+
+// UploadPhotoContext is the context-aware version of UploadPhoto.
+func (insta *Instagram) UploadPhotoContext(ctx context.Context, photo io.Reader, photoCaption string, quality int, filterType int) (Item, error) {
 	out := Item{}
 
-	config, err := insta.postPhoto(photo, photoCaption, quality, filterType, false)
+	config, err := insta.postPhoto(ctx, photo, photoCaption, quality, filterType, false)
 	if err != nil {
 		return out, err
 	}
@@ -1186,9 +1288,10 @@ func (insta *Instagram) UploadPhoto(photo io.Reader, photoCaption string, qualit
 		return out, err
 	}
 
-	body, err := insta.sendRequest(&reqOptions{
+	insta.logger().Info("configuring photo", "upload_id", config["upload_id"])
+	body, err := insta.sendRequest(ctx, &reqOptions{
 		Endpoint: "media/configure/?",
-		Query:    generateSignature(data),
+		Query:    insta.generateSignature(data),
 		IsPost:   true,
 	})
 	if err != nil {
@@ -1199,7 +1302,7 @@ func (insta *Instagram) UploadPhoto(photo io.Reader, photoCaption string, qualit
 		UploadID string `json:"upload_id"`
 		Status   string `json:"status"`
 	}
-	err = json.Unmarshal(body, &uploadResult)
+	err = insta.unmarshal(body, &uploadResult)
 	if err != nil {
 		return out, err
 	}
@@ -1211,14 +1314,14 @@ func (insta *Instagram) UploadPhoto(photo io.Reader, photoCaption string, qualit
 	return uploadResult.Media, nil
 }
 
-func (insta *Instagram) postPhoto(photo io.Reader, photoCaption string, quality int, filterType int, isSidecar bool) (map[string]interface{}, error) {
+func (insta *Instagram) postPhoto(ctx context.Context, photo io.Reader, photoCaption string, quality int, filterType int, isSidecar bool) (map[string]interface{}, error) {
 	uploadID := time.Now().Unix()
 	photoName := fmt.Sprintf("pending_media_%d.jpg", uploadID)
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
 	w.WriteField("upload_id", strconv.FormatInt(uploadID, 10))
-	w.WriteField("_uuid", insta.uuid)
-	w.WriteField("_csrftoken", insta.token)
+	w.WriteField("_uuid", insta.uuid.Get())
+	w.WriteField("_csrftoken", insta.token.Get())
 	var compression = map[string]interface{}{
 		"lib_name":    "jt",
 		"lib_version": "1.3.0",
@@ -1234,50 +1337,38 @@ func (insta *Instagram) postPhoto(photo io.Reader, photoCaption string, quality
 		return nil, err
 	}
 	var buf bytes.Buffer
-	rdr := io.TeeReader(photo, &buf)
+	rdr := io.TeeReader(contextReader{ctx, photo}, &buf)
 	if _, err = io.Copy(fw, rdr); err != nil {
 		return nil, err
 	}
 	if err := w.Close(); err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", goInstaAPIUrl+"upload/photo/", &b)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("X-IG-Capabilities", "3Q4=")
-	req.Header.Set("X-IG-Connection-Type", "WIFI")
-	req.Header.Set("Cookie2", "$Version=1")
-	req.Header.Set("Accept-Language", "en-US")
-	req.Header.Set("Accept-Encoding", "gzip, deflate")
-	req.Header.Set("Content-type", w.FormDataContentType())
-	req.Header.Set("Connection", "close")
-	req.Header.Set("User-Agent", goInstaUserAgent)
-
-	resp, err := insta.c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("invalid status code, result: %s", resp.Status)
+	var body []byte
+	dryRunQuery := map[string]string{"upload_id": strconv.FormatInt(uploadID, 10)}
+	if insta.record("POST", urlUploadPhoto, dryRunQuery) {
+		body = []byte(fmt.Sprintf(`{"status":"ok","upload_id":"%d"}`, uploadID))
+	} else {
+		insta.logger().Info("uploading photo", "upload_id", uploadID, "size", b.Len(), "sidecar", isSidecar)
+		body, err = insta.sendPhoto(ctx, &b, w.FormDataContentType())
+		if err != nil {
+			insta.logger().Warn("photo upload failed", "upload_id", uploadID, "error", err)
+			return nil, err
+		}
 	}
 	var result struct {
 		UploadID       string      `json:"upload_id"`
 		XsharingNonces interface{} `json:"xsharing_nonces"`
 		Status         string      `json:"status"`
 	}
-	err = json.Unmarshal(body, &result)
+	err = insta.unmarshal(body, &result)
 	if err != nil {
 		return nil, err
 	}
 	if result.Status != "ok" {
 		return nil, fmt.Errorf("unknown error, status: %s", result.Status)
 	}
+	insta.logger().Info("photo uploaded", "upload_id", uploadID)
 	width, height, err := getImageDimensionFromReader(&buf)
 	if err != nil {
 		return nil, err
@@ -1287,7 +1378,7 @@ func (insta *Instagram) postPhoto(photo io.Reader, photoCaption string, quality
 		"source_type":  4,
 		"caption":      photoCaption,
 		"upload_id":    strconv.FormatInt(uploadID, 10),
-		"device":       goInstaDeviceSettings,
+		"device":       insta.device.settings(),
 		"edits": map[string]interface{}{
 			"crop_original_size": []int{width * 1.0, height * 1.0},
 			"crop_center":        []float32{0.0, 0.0},
@@ -1302,13 +1393,75 @@ func (insta *Instagram) postPhoto(photo io.Reader, photoCaption string, quality
 	return config, nil
 }
 
+// sendPhoto sends the multipart body of a photo upload.
+func (insta *Instagram) sendPhoto(ctx context.Context, b *bytes.Buffer, contentType string) ([]byte, error) {
+	if err := insta.wait(ctx, CategoryUpload); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", insta.Hosts().Upload+urlUploadPhoto, b)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-IG-Capabilities", insta.app.Capabilities)
+	req.Header.Set("X-IG-Connection-Type", "WIFI")
+	req.Header.Set("Cookie2", "$Version=1")
+	req.Header.Set("Accept-Language", insta.locale)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	req.Header.Set("Content-type", contentType)
+	req.Header.Set("Connection", "close")
+	req.Header.Set("User-Agent", insta.userAgent())
+
+	stats := RequestStats{
+		Endpoint:  endpointLabel(urlUploadPhoto),
+		Method:    "POST",
+		BytesSent: int64(b.Len()),
+	}
+	start := time.Now()
+	defer func() {
+		stats.Latency = time.Since(start)
+		insta.observe(stats)
+	}()
+
+	resp, err := insta.client().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			stats.Err = ctx.Err()
+			return nil, ctx.Err()
+		}
+		stats.Err = networkError{err}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	stats.StatusCode = resp.StatusCode
+	body, received, err := readBody(resp)
+	stats.BytesReceived = received
+	if err != nil {
+		if ctx.Err() != nil {
+			stats.Err = ctx.Err()
+			return nil, ctx.Err()
+		}
+		stats.Err = networkError{err}
+		return nil, err
+	}
+	if err = isError(urlUploadPhoto, resp.StatusCode, body); err != nil {
+		stats.Err = err
+		return nil, err
+	}
+	return body, nil
+}
+
 // UploadAlbum post image from io.Reader to instagram.
 func (insta *Instagram) UploadAlbum(photos []io.Reader, photoCaption string, quality int, filterType int) (Item, error) {
+	return insta.UploadAlbumContext(context.Background(), photos, photoCaption, quality, filterType)
+}
+
+// UploadAlbumContext is the context-aware version of UploadAlbum.
+func (insta *Instagram) UploadAlbumContext(ctx context.Context, photos []io.Reader, photoCaption string, quality int, filterType int) (Item, error) {
 	out := Item{}
 
 	var childrenMetadata []map[string]interface{}
 	for _, photo := range photos {
-		config, err := insta.postPhoto(photo, photoCaption, quality, filterType, true)
+		config, err := insta.postPhoto(ctx, photo, photoCaption, quality, filterType, true)
 		if err != nil {
 			return out, err
 		}
@@ -1327,9 +1480,10 @@ func (insta *Instagram) UploadAlbum(photos []io.Reader, photoCaption string, qua
 		return out, err
 	}
 
-	body, err := insta.sendRequest(&reqOptions{
+	insta.logger().Info("configuring album", "client_sidecar_id", albumUploadID, "children", len(childrenMetadata))
+	body, err := insta.sendRequest(ctx, &reqOptions{
 		Endpoint: "media/configure_sidecar/?",
-		Query:    generateSignature(data),
+		Query:    insta.generateSignature(data),
 		IsPost:   true,
 	})
 	if err != nil {
@@ -1341,7 +1495,7 @@ func (insta *Instagram) UploadAlbum(photos []io.Reader, photoCaption string, qua
 		ClientSideCarID int64  `json:"client_sidecar_id"`
 		Status          string `json:"status"`
 	}
-	err = json.Unmarshal(body, &uploadResult)
+	err = insta.unmarshal(body, &uploadResult)
 	if err != nil {
 		return out, err
 	}
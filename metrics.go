@@ -0,0 +1,279 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestStats describes a single HTTP request sent by goinsta.
+type RequestStats struct {
+	// Endpoint is the normalized endpoint, with ids replaced by ":id"
+	// and user names or tags by ":name".
+	Endpoint   string
+	Method     string
+	StatusCode int
+	Latency    time.Duration
+	// BytesSent is the size of the request body.
+	BytesSent int64
+	// BytesReceived is the size of the response body.
+	BytesReceived int64
+	Err           error
+}
+
+// Metrics receives statistics of every HTTP request, including retries,
+// photo uploads and media downloads.
+//
+// ObserveRequest can be called from several goroutines at once.
+type Metrics interface {
+	ObserveRequest(stats RequestStats)
+}
+
+// SetMetrics sets the metrics sink of the session. nil disables metrics.
+func (inst *Instagram) SetMetrics(m Metrics) {
+	inst.mu.Lock()
+	inst.metrics = m
+	inst.mu.Unlock()
+}
+
+func (inst *Instagram) observe(stats RequestStats) {
+	inst.mu.RLock()
+	m := inst.metrics
+	inst.mu.RUnlock()
+	if m != nil {
+		m.ObserveRequest(stats)
+	}
+}
+
+// endpointLabel normalizes endpoint so that it can be used as a metric label.
+func endpointLabel(endpoint string) string {
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+	parts := strings.Split(endpoint, "/")
+	for i, p := range parts {
+		switch {
+		case p == "":
+		case isNumericID(p):
+			parts[i] = ":id"
+		case i > 0 && parts[i-1] == "tag",
+			i > 0 && i+2 < len(parts) && (parts[i-1] == "users" || parts[i-1] == "tags"):
+			parts[i] = ":name"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// isNumericID reports whether s is a numeric id such as 123 or 123_456.
+func isNumericID(s string) bool {
+	for i := range s {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '_' {
+			return false
+		}
+	}
+	return s[0] != '_'
+}
+
+// errorLabel returns a short name of err usable as a metric label.
+func errorLabel(err error) string {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.ErrorType != "":
+		return apiErr.ErrorType
+	case errors.As(err, &apiErr):
+		return "http_" + strconv.Itoa(apiErr.StatusCode)
+	case errors.Is(err, ErrNetwork):
+		return "network"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	}
+	return "other"
+}
+
+// DefaultLatencyBuckets are the latency histogram upper bounds, in seconds,
+// used by NewMemoryMetrics.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Histogram is a latency histogram.
+type Histogram struct {
+	// Buckets are the upper bounds in seconds.
+	Buckets []float64
+	// Counts[i] is the number of observations lower or equal to Buckets[i].
+	Counts []int64
+	Count  int64
+	// Sum is the sum of observations in seconds.
+	Sum float64
+}
+
+func (h *Histogram) observe(d time.Duration) {
+	s := d.Seconds()
+	for i, b := range h.Buckets {
+		if s <= b {
+			h.Counts[i]++
+		}
+	}
+	h.Count++
+	h.Sum += s
+}
+
+// EndpointMetrics are the statistics of one endpoint.
+type EndpointMetrics struct {
+	Requests int64
+	// Errors are the failed requests by error type.
+	Errors  map[string]int64
+	Latency Histogram
+}
+
+// MetricsSnapshot is a copy of the statistics collected by MemoryMetrics.
+type MetricsSnapshot struct {
+	Endpoints     map[string]EndpointMetrics
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// MemoryMetrics is a Metrics implementation keeping statistics in memory.
+// It can be shared by several sessions.
+type MemoryMetrics struct {
+	mu            sync.Mutex
+	buckets       []float64
+	endpoints     map[string]*EndpointMetrics
+	bytesSent     int64
+	bytesReceived int64
+}
+
+// NewMemoryMetrics creates a MemoryMetrics using buckets as latency
+// histogram upper bounds, or DefaultLatencyBuckets when buckets is empty.
+func NewMemoryMetrics(buckets ...float64) *MemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &MemoryMetrics{
+		buckets:   buckets,
+		endpoints: make(map[string]*EndpointMetrics),
+	}
+}
+
+// ObserveRequest implements Metrics.
+func (m *MemoryMetrics) ObserveRequest(stats RequestStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.endpoints[stats.Endpoint]
+	if !ok {
+		e = &EndpointMetrics{
+			Errors: make(map[string]int64),
+			Latency: Histogram{
+				Buckets: m.buckets,
+				Counts:  make([]int64, len(m.buckets)),
+			},
+		}
+		m.endpoints[stats.Endpoint] = e
+	}
+	e.Requests++
+	if stats.Err != nil {
+		e.Errors[errorLabel(stats.Err)]++
+	}
+	e.Latency.observe(stats.Latency)
+	m.bytesSent += stats.BytesSent
+	m.bytesReceived += stats.BytesReceived
+}
+
+// Snapshot returns a copy of the collected statistics.
+func (m *MemoryMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := MetricsSnapshot{
+		Endpoints:     make(map[string]EndpointMetrics, len(m.endpoints)),
+		BytesSent:     m.bytesSent,
+		BytesReceived: m.bytesReceived,
+	}
+	for name, e := range m.endpoints {
+		c := *e
+		c.Errors = make(map[string]int64, len(e.Errors))
+		for k, v := range e.Errors {
+			c.Errors[k] = v
+		}
+		c.Latency.Counts = append([]int64(nil), e.Latency.Counts...)
+		s.Endpoints[name] = c
+	}
+	return s
+}
+
+// PrometheusHandler serves the snapshot of m in the Prometheus text format.
+func PrometheusHandler(m *MemoryMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.Snapshot().WritePrometheus(w)
+	})
+}
+
+// WritePrometheus writes the snapshot in the Prometheus text format.
+func (s MetricsSnapshot) WritePrometheus(w io.Writer) error {
+	names := make([]string, 0, len(s.Endpoints))
+	for name := range s.Endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP goinsta_requests_total HTTP requests sent to instagram.\n")
+	b.WriteString("# TYPE goinsta_requests_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "goinsta_requests_total{endpoint=%s} %d\n", promLabel(name), s.Endpoints[name].Requests)
+	}
+
+	b.WriteString("# HELP goinsta_request_errors_total Failed HTTP requests by error type.\n")
+	b.WriteString("# TYPE goinsta_request_errors_total counter\n")
+	for _, name := range names {
+		errs := s.Endpoints[name].Errors
+		types := make([]string, 0, len(errs))
+		for t := range errs {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			fmt.Fprintf(&b, "goinsta_request_errors_total{endpoint=%s,error_type=%s} %d\n", promLabel(name), promLabel(t), errs[t])
+		}
+	}
+
+	b.WriteString("# HELP goinsta_request_duration_seconds HTTP request latency.\n")
+	b.WriteString("# TYPE goinsta_request_duration_seconds histogram\n")
+	for _, name := range names {
+		h := s.Endpoints[name].Latency
+		for i, le := range h.Buckets {
+			fmt.Fprintf(&b, "goinsta_request_duration_seconds_bucket{endpoint=%s,le=\"%s\"} %d\n",
+				promLabel(name), strconv.FormatFloat(le, 'g', -1, 64), h.Counts[i])
+		}
+		fmt.Fprintf(&b, "goinsta_request_duration_seconds_bucket{endpoint=%s,le=\"+Inf\"} %d\n", promLabel(name), h.Count)
+		fmt.Fprintf(&b, "goinsta_request_duration_seconds_sum{endpoint=%s} %s\n", promLabel(name), strconv.FormatFloat(h.Sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "goinsta_request_duration_seconds_count{endpoint=%s} %d\n", promLabel(name), h.Count)
+	}
+
+	b.WriteString("# HELP goinsta_bytes_sent_total Bytes uploaded to instagram.\n")
+	b.WriteString("# TYPE goinsta_bytes_sent_total counter\n")
+	fmt.Fprintf(&b, "goinsta_bytes_sent_total %d\n", s.BytesSent)
+	b.WriteString("# HELP goinsta_bytes_received_total Bytes downloaded from instagram.\n")
+	b.WriteString("# TYPE goinsta_bytes_received_total counter\n")
+	fmt.Fprintf(&b, "goinsta_bytes_received_total %d\n", s.BytesReceived)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promLabel(s string) string {
+	return `"` + promEscaper.Replace(s) + `"`
+}
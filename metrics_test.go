@@ -0,0 +1,73 @@
+package goinsta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEndpointLabel(t *testing.T) {
+	tests := map[string]string{
+		"feed/timeline/":                     "feed/timeline/",
+		"users/goinsta/usernameinfo/":        "users/:name/usernameinfo/",
+		"users/search/":                      "users/search/",
+		"media/123_456/comment/789/delete/":  "media/:id/comment/:id/delete/",
+		"feed/tag/golang/?max_id=QVFE":       "feed/tag/:name/",
+		"friendships/create/1234/":           "friendships/create/:id/",
+		"direct_v2/threads/340282366841710/": "direct_v2/threads/:id/",
+	}
+	for endpoint, want := range tests {
+		if got := endpointLabel(endpoint); got != want {
+			t.Errorf("endpointLabel(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestMemoryMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/info/") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":"fail","message":"User not found"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta := New("user", "pass")
+	if err := insta.SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	m := NewMemoryMetrics()
+	insta.SetMetrics(m)
+
+	insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline})
+	insta.sendRequest(context.Background(), &reqOptions{Endpoint: "users/1/info/"})
+	insta.sendRequest(context.Background(), &reqOptions{Endpoint: "users/2/info/"})
+
+	s := m.Snapshot()
+	if e := s.Endpoints[urlTimeline]; e.Requests != 1 || len(e.Errors) != 0 || e.Latency.Count != 1 {
+		t.Fatalf("unexpected timeline metrics: %+v", e)
+	}
+	if e := s.Endpoints["users/:id/info/"]; e.Requests != 2 || e.Errors["http_404"] != 2 {
+		t.Fatalf("unexpected user info metrics: %+v", e)
+	}
+	if s.BytesReceived == 0 {
+		t.Fatal("no received bytes counted")
+	}
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(m).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+	for _, line := range []string{
+		`goinsta_requests_total{endpoint="users/:id/info/"} 2`,
+		`goinsta_request_errors_total{endpoint="users/:id/info/",error_type="http_404"} 2`,
+		`goinsta_request_duration_seconds_count{endpoint="feed/timeline/"} 1`,
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
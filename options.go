@@ -0,0 +1,143 @@
+package goinsta
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// Option configures an Instagram session. See NewWithOptions and ImportConfig.
+type Option func(inst *Instagram) error
+
+// NewWithOptions creates Instagram structure configured by opts.
+func NewWithOptions(username, password string, opts ...Option) (*Instagram, error) {
+	inst := New(username, password)
+	if err := inst.apply(opts); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func (inst *Instagram) apply(opts []Option) error {
+	for _, opt := range opts {
+		if err := opt(inst); err != nil {
+			return err
+		}
+	}
+	if inst.c.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+		inst.c.Jar = jar
+	}
+	return nil
+}
+
+// WithHTTPClient sets the http client used by the session.
+//
+// The client is copied. A cookie jar is created if the client has none.
+func WithHTTPClient(client *http.Client) Option {
+	return func(inst *Instagram) error {
+		c := *client
+		if c.Jar == nil {
+			c.Jar = inst.c.Jar
+		}
+		inst.c = &c
+		return nil
+	}
+}
+
+// WithTransport sets the http transport used by the session.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(inst *Instagram) error {
+		inst.c.Transport = transport
+		return nil
+	}
+}
+
+// WithProxy sets proxy for connection. See SetProxy.
+func WithProxy(url string, insecure bool) Option {
+	return func(inst *Instagram) error {
+		return inst.SetProxy(url, insecure)
+	}
+}
+
+// WithDeviceIDs sets the device id, uuid and phone id of the session.
+// Empty values are left unchanged.
+func WithDeviceIDs(deviceID, uuid, phoneID string) Option {
+	return func(inst *Instagram) error {
+		if deviceID != "" {
+			inst.dID.Set(deviceID)
+		}
+		if uuid != "" {
+			inst.uuid.Set(uuid)
+		}
+		if phoneID != "" {
+			inst.pid.Set(phoneID)
+		}
+		return nil
+	}
+}
+
+// WithHosts sets the base URLs used to reach instagram. See SetHosts.
+func WithHosts(hosts Hosts) Option {
+	return func(inst *Instagram) error {
+		inst.SetHosts(hosts)
+		return nil
+	}
+}
+
+// WithBaseURL points every host below base. See SetBaseURL.
+func WithBaseURL(base string) Option {
+	return func(inst *Instagram) error {
+		return inst.SetBaseURL(base)
+	}
+}
+
+// WithLocale sets the locale sent in the Accept-Language header, like "en-US".
+func WithLocale(locale string) Option {
+	return func(inst *Instagram) error {
+		inst.locale = locale
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the session. See SetLogger.
+func WithLogger(l Logger) Option {
+	return func(inst *Instagram) error {
+		inst.SetLogger(l)
+		return nil
+	}
+}
+
+// WithLimiter sets the limiter of the session. See SetLimiter.
+func WithLimiter(l Limiter) Option {
+	return func(inst *Instagram) error {
+		inst.SetLimiter(l)
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the session. See SetRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(inst *Instagram) error {
+		inst.SetRetryPolicy(policy)
+		return nil
+	}
+}
+
+// WithInterceptors appends interceptors to the session. See Use.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(inst *Instagram) error {
+		inst.Use(interceptors...)
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics sink of the session. See SetMetrics.
+func WithMetrics(m Metrics) Option {
+	return func(inst *Instagram) error {
+		inst.SetMetrics(m)
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package goinsta
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	var lang string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang = r.Header.Get("Accept-Language")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	opts := []Option{
+		WithBaseURL(srv.URL),
+		WithLocale("fr-FR"),
+		WithDeviceIDs("android-0123456789abcdef", "", ""),
+	}
+	insta, err := NewWithOptions("user", "pass", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline}); err != nil {
+		t.Fatal(err)
+	}
+	if lang != "fr-FR" || insta.dID.Get() != "android-0123456789abcdef" {
+		t.Fatalf("options not applied: locale %q, device id %q", lang, insta.dID.Get())
+	}
+
+	// an imported session is configured by the same options
+	var buf bytes.Buffer
+	if err = Export(insta, &buf); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportReader(&buf, WithLocale("fr-FR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Hosts() != insta.Hosts() || imported.locale != "fr-FR" || imported.dID.Get() != insta.dID.Get() {
+		t.Fatalf("imported session differs: %+v", imported.Hosts())
+	}
+
+	if _, err = NewWithOptions("user", "pass", WithBaseURL("://")); err == nil {
+		t.Fatal("invalid base URL accepted")
+	}
+}
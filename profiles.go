@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -19,10 +19,15 @@ func newProfiles(inst *Instagram) *Profiles {
 
 // ByName return a *User structure parsed by username
 func (prof *Profiles) ByName(name string) (*User, error) {
-	body, err := prof.inst.sendSimpleRequest(urlUserByName, name)
+	return prof.ByNameContext(context.Background(), name)
+}
+
+// ByNameContext is the context-aware version of ByName.
+func (prof *Profiles) ByNameContext(ctx context.Context, name string) (*User, error) {
+	body, err := prof.inst.sendSimpleRequest(ctx, urlUserByName, name)
 	if err == nil {
 		resp := userResp{}
-		err = json.Unmarshal(body, &resp)
+		err = prof.inst.unmarshal(body, &resp)
 		if err == nil {
 			user := &resp.User
 			user.inst = prof.inst
@@ -34,20 +39,25 @@ func (prof *Profiles) ByName(name string) (*User, error) {
 
 // ByID returns a *User structure parsed by user id
 func (prof *Profiles) ByID(id int64) (*User, error) {
+	return prof.ByIDContext(context.Background(), id)
+}
+
+// ByIDContext is the context-aware version of ByID.
+func (prof *Profiles) ByIDContext(ctx context.Context, id int64) (*User, error) {
 	data, err := prof.inst.prepareData()
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := prof.inst.sendRequest(
+	body, err := prof.inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserByID, id),
-			Query:    generateSignature(data),
+			Query:    prof.inst.generateSignature(data),
 		},
 	)
 	if err == nil {
 		resp := userResp{}
-		err = json.Unmarshal(body, &resp)
+		err = prof.inst.unmarshal(body, &resp)
 		if err == nil {
 			user := &resp.User
 			user.inst = prof.inst
@@ -59,10 +69,15 @@ func (prof *Profiles) ByID(id int64) (*User, error) {
 
 // Blocked returns a list of blocked profiles.
 func (prof *Profiles) Blocked() ([]BlockedUser, error) {
-	body, err := prof.inst.sendSimpleRequest(urlBlockedList)
+	return prof.BlockedContext(context.Background())
+}
+
+// BlockedContext is the context-aware version of Blocked.
+func (prof *Profiles) BlockedContext(ctx context.Context) ([]BlockedUser, error) {
+	body, err := prof.inst.sendSimpleRequest(ctx, urlBlockedList)
 	if err == nil {
 		resp := blockedListResp{}
-		err = json.Unmarshal(body, &resp)
+		err = prof.inst.unmarshal(body, &resp)
 		return resp.BlockedList, err
 	}
 	return nil, err
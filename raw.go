@@ -0,0 +1,68 @@
+package goinsta
+
+import (
+	"context"
+	"fmt"
+)
+
+// Request is an API request sent by Do, for endpoints goinsta does not wrap.
+type Request struct {
+	// Method is GET or POST. Default is GET.
+	Method string
+	// Endpoint is the request path relative to the API host, like "users/123/info/".
+	Endpoint string
+	// UseV2 sends the request to the v2 API host.
+	UseV2 bool
+	// Query are the plain request parameters. They are sent in the URL
+	// of GET requests and as form body of POST requests.
+	Query map[string]string
+	// Data, if set, is signed and sent as body of a POST request,
+	// along with the session identifiers (_uuid, _csrftoken and _uid).
+	// Query parameters are added next to the signed body.
+	Data map[string]interface{}
+	// Idempotent marks POST requests that can be retried safely.
+	// See RetryPolicy.
+	Idempotent bool
+	// Result, if set, receives the JSON decoded response.
+	Result interface{}
+}
+
+// Do sends r using the session cookies, headers, retry policy, limiter
+// and interceptors, and returns the response body.
+//
+// Failed requests return the same errors as the other methods (see APIError).
+func (inst *Instagram) Do(ctx context.Context, r Request) ([]byte, error) {
+	o := &reqOptions{
+		Endpoint:   r.Endpoint,
+		UseV2:      r.UseV2,
+		Idempotent: r.Idempotent,
+		Query:      r.Query,
+	}
+	switch r.Method {
+	case "", "GET":
+	case "POST":
+		o.IsPost = true
+	default:
+		return nil, fmt.Errorf("unsupported method %s", r.Method)
+	}
+
+	if r.Data != nil {
+		if !o.IsPost {
+			return nil, fmt.Errorf("signed data requires POST method")
+		}
+		data, err := inst.prepareData(r.Data)
+		if err != nil {
+			return nil, err
+		}
+		o.Query = inst.generateSignature(data)
+		for k, v := range r.Query {
+			o.Query[k] = v
+		}
+	}
+
+	body, err := inst.sendRequest(ctx, o)
+	if err == nil && r.Result != nil {
+		err = inst.unmarshal(body, r.Result)
+	}
+	return body, err
+}
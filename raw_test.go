@@ -0,0 +1,58 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	var form map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		if strings.HasSuffix(r.URL.Path, "/missing/") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":"fail","message":"Page not found"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok","count":3}`))
+	}))
+	defer srv.Close()
+
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res struct {
+		Count int `json:"count"`
+	}
+	_, err = insta.Do(context.Background(), Request{
+		Method:   "POST",
+		Endpoint: "some/endpoint/",
+		Data:     map[string]interface{}{"media_id": "1"},
+		Query:    map[string]string{"d": "0"},
+		Result:   &res,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 3 {
+		t.Fatalf("got count %d, want 3", res.Count)
+	}
+	if body := form["signed_body"]; len(body) != 1 || !strings.Contains(body[0], `"media_id":"1"`) || form["d"][0] != "0" {
+		t.Fatalf("unexpected form %v", form)
+	}
+
+	_, err = insta.Do(context.Background(), Request{Endpoint: "some/missing/"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+	if _, err = insta.Do(context.Background(), Request{Data: map[string]interface{}{}}); err == nil {
+		t.Fatal("signed GET request accepted")
+	}
+}
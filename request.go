@@ -2,12 +2,13 @@ package goinsta
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,9 +18,13 @@ type reqOptions struct {
 	// Connection is connection header. Default is "close".
 	Connection string
 
-	// Login process
+	// Login process.
 	Login bool
 
+	// Handshake is set on the requests of the login flow, which are sent
+	// even in dry-run mode.
+	Handshake bool
+
 	// Endpoint is the request path of instagram api
 	Endpoint string
 
@@ -31,21 +36,26 @@ type reqOptions struct {
 	// UseV2 is set when API endpoint uses v2 url.
 	UseV2 bool
 
+	// Idempotent is set when a POST request only reads data and can be
+	// sent again safely. GET requests are always idempotent.
+	Idempotent bool
+
 	// Query is the parameters of the request
 	//
 	// This parameters are independents of the request method (POST|GET)
 	Query map[string]string
 }
 
-func (insta *Instagram) sendSimpleRequest(uri string, a ...interface{}) (body []byte, err error) {
+func (insta *Instagram) sendSimpleRequest(ctx context.Context, uri string, a ...interface{}) (body []byte, err error) {
 	return insta.sendRequest(
+		ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(uri, a...),
 		},
 	)
 }
 
-func (insta *Instagram) sendRequest(o *reqOptions) (body []byte, err error) {
+func (insta *Instagram) sendRequest(ctx context.Context, o *reqOptions) (body []byte, err error) {
 	method := "GET"
 	if o.IsPost {
 		method = "POST"
@@ -54,24 +64,46 @@ func (insta *Instagram) sendRequest(o *reqOptions) (body []byte, err error) {
 		o.Connection = "keep-alive"
 	}
 
-	nu := goInstaAPIUrl
-	if o.UseV2 {
-		nu = goInstaAPIUrlv2
+	call := &Call{
+		Endpoint: o.Endpoint,
+		Method:   method,
+		Query:    o.Query,
+		UseV2:    o.UseV2,
+	}
+	call.viewer = insta.accountID()
+	send := func(ctx context.Context, call *Call) error {
+		return insta.send(ctx, o, call)
+	}
+	err = insta.chain(send)(ctx, call)
+	return call.Body, err
+}
+
+// send sends call to instagram following the retry policy.
+func (insta *Instagram) send(ctx context.Context, o *reqOptions, call *Call) error {
+	if o.mutates() && insta.record(call.Method, call.Endpoint, call.Query) {
+		call.StatusCode, call.Body = 200, []byte(dryRunResponse)
+		return nil
+	}
+
+	hosts := insta.Hosts()
+	nu := hosts.API
+	if call.UseV2 {
+		nu = hosts.APIv2
 	}
 
-	u, err := url.Parse(nu + o.Endpoint)
+	u, err := url.Parse(nu + call.Endpoint)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	vs := url.Values{}
 	bf := bytes.NewBuffer([]byte{})
 
-	for k, v := range o.Query {
+	for k, v := range call.Query {
 		vs.Add(k, v)
 	}
 
-	if o.IsPost {
+	if call.Method == "POST" {
 		bf.WriteString(vs.Encode())
 	} else {
 		for k, v := range u.Query() {
@@ -80,85 +112,129 @@ func (insta *Instagram) sendRequest(o *reqOptions) (body []byte, err error) {
 
 		u.RawQuery = vs.Encode()
 	}
+	payload, encoding := insta.compress(bf.Bytes())
+
+	log := insta.logger()
+	for attempt := 1; ; attempt++ {
+		if err = insta.wait(ctx, categoryOf(call.Endpoint)); err != nil {
+			return err
+		}
+
+		log.Debug("request", "method", call.Method, "endpoint", call.Endpoint, "attempt", attempt, "query", call.Query)
+		start := time.Now()
+		var received int64
+		received, err = insta.roundTrip(ctx, o, call, u.String(), payload, encoding)
+		latency := time.Since(start)
+		if err != nil {
+			log.Warn("request failed", "method", call.Method, "endpoint", call.Endpoint, "status", call.StatusCode, "duration", latency, "error", err)
+		} else {
+			log.Debug("response", "method", call.Method, "endpoint", call.Endpoint, "status", call.StatusCode, "duration", latency, "size", len(call.Body))
+		}
+		insta.observe(RequestStats{
+			Endpoint:      endpointLabel(call.Endpoint),
+			Method:        call.Method,
+			StatusCode:    call.StatusCode,
+			Latency:       latency,
+			BytesSent:     int64(len(payload)),
+			BytesReceived: received,
+			Err:           err,
+		})
+
+		wait, retry := insta.retryPolicy().next(attempt, call.Method, o, err)
+		if !retry {
+			return err
+		}
+		log.Info("retrying request", "method", call.Method, "endpoint", call.Endpoint, "attempt", attempt+1, "wait", wait)
+		if err = sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
 
-	var req *http.Request
-	req, err = http.NewRequest(method, u.String(), bf)
+// roundTrip sends a single request to instagram and stores the response in call.
+// It returns the number of bytes received.
+func (insta *Instagram) roundTrip(ctx context.Context, o *reqOptions, call *Call, u string, payload []byte, encoding string) (int64, error) {
+	call.StatusCode, call.Body = 0, nil
+	req, err := http.NewRequestWithContext(ctx, call.Method, u, bytes.NewReader(payload))
 	if err != nil {
-		return
+		return 0, err
 	}
 
 	req.Header.Set("Connection", o.Connection)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
-	req.Header.Set("Accept-Language", "en-US")
-	req.Header.Set("User-Agent", goInstaUserAgent)
-	req.Header.Set("X-IG-App-ID", fbAnalytics)
-	req.Header.Set("X-IG-Capabilities", igCapabilities)
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	req.Header.Set("Accept-Language", insta.locale)
+	req.Header.Set("User-Agent", insta.userAgent())
+	req.Header.Set("X-IG-App-ID", insta.app.AppID)
+	req.Header.Set("X-IG-Capabilities", insta.app.Capabilities)
 	req.Header.Set("X-IG-Connection-Type", connType)
 	req.Header.Set("X-IG-Connection-Speed", fmt.Sprintf("%dkbps", acquireRand(1000, 3700)))
 	req.Header.Set("X-IG-Bandwidth-Speed-KBPS", "-1.000")
 	req.Header.Set("X-IG-Bandwidth-TotalBytes-B", "0")
 	req.Header.Set("X-IG-Bandwidth-TotalTime-MS", "0")
 
-	resp, err := insta.c.Do(req)
+	c := insta.client()
+	cu, _ := insta.Hosts().apiURL()
+	before := cookieState(c.Jar.Cookies(cu))
+	resp, err := c.Do(req)
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, networkError{err}
 	}
 	defer resp.Body.Close()
 
-	u, _ = url.Parse(goInstaAPIUrl)
-	for _, value := range insta.c.Jar.Cookies(u) {
-		if strings.Contains(value.Name, "csrftoken") {
-			insta.token = value.Value
+	cookies := c.Jar.Cookies(cu)
+	for _, value := range cookies {
+		if strings.Contains(value.Name, "csrftoken") && insta.token.Get() != value.Value {
+			insta.token.Set(value.Value)
+			insta.logger().Debug("csrf token refreshed", "token", value.Value)
 		}
 	}
-
-	body, err = ioutil.ReadAll(resp.Body)
-	if err == nil {
-		err = isError(resp.StatusCode, body)
+	// most responses set the same cookies again, only save the changes
+	if cookieState(cookies) != before {
+		insta.logger().Debug("cookies updated", "endpoint", call.Endpoint, "cookies", resp.Cookies())
+		insta.sessionRefreshed(false)
+		insta.autoSave("cookies")
 	}
-	return body, err
-}
 
-func isError(code int, body []byte) (err error) {
-	switch code {
-	case 200:
-	case 503:
-		return Error503{
-			Message: "Instagram API error. Try it later.",
-		}
-	case 400:
-		ierr := Error400{}
-		err = json.Unmarshal(body, &ierr)
-		if err != nil {
-			return err
+	body, received, err := readBody(resp)
+	if err != nil {
+		if ctx.Err() != nil {
+			return received, ctx.Err()
 		}
+		return received, networkError{err}
+	}
+	call.StatusCode, call.Body = resp.StatusCode, body
 
-		if ierr.Message == "challenge_required" {
-			return ierr.ChallengeError
-
-		}
+	err = call.StatusError()
+	if apiErr, ok := err.(*APIError); ok {
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	return received, err
+}
 
-		if err == nil && ierr.Message != "" {
-			return ierr
-		}
-	default:
-		ierr := ErrorN{}
-		err = json.Unmarshal(body, &ierr)
-		if err != nil {
-			return err
-		}
-		return ierr
+// cookieState returns a string changing with the names and values of cookies.
+func cookieState(cookies []*http.Cookie) string {
+	state := make([]string, len(cookies))
+	for i, cookie := range cookies {
+		state[i] = cookie.Name + "=" + cookie.Value
 	}
-	return nil
+	sort.Strings(state)
+	return strings.Join(state, "; ")
 }
 
 func (insta *Instagram) prepareData(other ...map[string]interface{}) (string, error) {
 	data := map[string]interface{}{
-		"_uuid":      insta.uuid,
-		"_csrftoken": insta.token,
+		"_uuid":      insta.uuid.Get(),
+		"_csrftoken": insta.token.Get(),
 	}
-	if insta.Account != nil && insta.Account.ID != 0 {
-		data["_uid"] = strconv.FormatInt(insta.Account.ID, 10)
+	if id := insta.accountID(); id != 0 {
+		data["_uid"] = strconv.FormatInt(id, 10)
 	}
 
 	for i := range other {
@@ -175,8 +251,8 @@ func (insta *Instagram) prepareData(other ...map[string]interface{}) (string, er
 
 func (insta *Instagram) prepareDataQuery(other ...map[string]interface{}) map[string]string {
 	data := map[string]string{
-		"_uuid":      insta.uuid,
-		"_csrftoken": insta.token,
+		"_uuid":      insta.uuid.Get(),
+		"_csrftoken": insta.token.Get(),
 	}
 	for i := range other {
 		for key, value := range other[i] {
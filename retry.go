@@ -0,0 +1,121 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are sent again.
+//
+// The zero value disables retries. POST requests which change something
+// (uploads, comments, messages...) are never sent again unless
+// RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum wait between two attempts.
+	//
+	// When instagram asks to wait longer using the Retry-After header
+	// the error is returned instead.
+	MaxBackoff time.Duration
+	// Multiplier is the growth factor of the wait after every attempt.
+	Multiplier float64
+	// Jitter randomizes every wait by up to Jitter*wait (0 to 1).
+	Jitter float64
+	// RetryOn are the kinds of errors to retry, compared using errors.Is.
+	// See ErrServerError, ErrRateLimited and ErrNetwork.
+	RetryOn []error
+	// RetryNonIdempotent allows sending again POST requests with side effects.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy retrying server errors, rate limits
+// and network errors up to 4 times with exponential backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  time.Second,
+		MaxBackoff:  time.Minute,
+		Multiplier:  2,
+		Jitter:      0.2,
+		RetryOn:     []error{ErrServerError, ErrRateLimited, ErrNetwork},
+	}
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests.
+func (inst *Instagram) SetRetryPolicy(policy RetryPolicy) {
+	inst.mu.Lock()
+	inst.retry = policy
+	inst.mu.Unlock()
+}
+
+func (inst *Instagram) retryPolicy() RetryPolicy {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.retry
+}
+
+// next returns the time to wait before sending again a request failed with err.
+func (p RetryPolicy) next(attempt int, method string, o *reqOptions, err error) (time.Duration, bool) {
+	if err == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if method != "GET" && !o.Idempotent && !p.RetryNonIdempotent {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+	retry := false
+	for _, kind := range p.RetryOn {
+		if errors.Is(err, kind) {
+			retry = true
+			break
+		}
+	}
+	if !retry {
+		return 0, false
+	}
+
+	wait := p.backoff(attempt)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+		if p.MaxBackoff > 0 && apiErr.RetryAfter > p.MaxBackoff {
+			return 0, false
+		}
+		wait = apiErr.RetryAfter
+	}
+	return wait, true
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+	wait := float64(p.MinBackoff) * math.Pow(mult, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
+
+// sleepContext waits d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
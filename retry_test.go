@@ -0,0 +1,45 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":"fail"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	insta := New("user", "pass")
+	if err := insta.SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	policy := DefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+	insta.SetRetryPolicy(policy)
+
+	_, err := insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline})
+	if err != nil || hits != 3 {
+		t.Fatalf("got %v after %d attempts, want success after 3", err, hits)
+	}
+
+	// non idempotent requests are not sent again
+	hits = 0
+	_, err = insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlInboxSend, IsPost: true})
+	if !errors.Is(err, ErrServerError) || hits != 1 {
+		t.Fatalf("got %v after %d attempts, want ErrServerError after 1", err, hits)
+	}
+}
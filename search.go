@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -72,20 +72,25 @@ func newSearch(inst *Instagram) *Search {
 
 // User search by username, you can use count optional parameter to get more than 50 items.
 func (search *Search) User(user string, countParam ...int) (*SearchResult, error) {
+	return search.UserContext(context.Background(), user, countParam...)
+}
+
+// UserContext is the context-aware version of User.
+func (search *Search) UserContext(ctx context.Context, user string, countParam ...int) (*SearchResult, error) {
 	count := 50
 	if len(countParam) > 0 {
 		count = countParam[0]
 	}
 	insta := search.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSearchUser,
 			Query: map[string]string{
-				"ig_sig_key_version": goInstaSigKeyVersion,
+				"ig_sig_key_version": insta.app.SigKeyVersion,
 				"is_typeahead":       "true",
 				"q":                  user,
 				"count":              fmt.Sprintf("%d", count),
-				"rank_token":         insta.rankToken,
+				"rank_token":         insta.rankToken.Get(),
 			},
 		},
 	)
@@ -94,7 +99,7 @@ func (search *Search) User(user string, countParam ...int) (*SearchResult, error
 	}
 
 	res := &SearchResult{}
-	err = json.Unmarshal(body, res)
+	err = insta.unmarshal(body, res)
 	for id := range res.Users {
 		res.Users[id].inst = insta
 	}
@@ -103,13 +108,18 @@ func (search *Search) User(user string, countParam ...int) (*SearchResult, error
 
 // Tags search by tag
 func (search *Search) Tags(tag string) (*SearchResult, error) {
+	return search.TagsContext(context.Background(), tag)
+}
+
+// TagsContext is the context-aware version of Tags.
+func (search *Search) TagsContext(ctx context.Context, tag string) (*SearchResult, error) {
 	insta := search.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSearchTag,
 			Query: map[string]string{
 				"is_typeahead": "true",
-				"rank_token":   insta.rankToken,
+				"rank_token":   insta.rankToken.Get(),
 				"q":            tag,
 			},
 		},
@@ -119,7 +129,7 @@ func (search *Search) Tags(tag string) (*SearchResult, error) {
 	}
 
 	res := &SearchResult{}
-	err = json.Unmarshal(body, res)
+	err = insta.unmarshal(body, res)
 	return res, err
 }
 
@@ -127,9 +137,14 @@ func (search *Search) Tags(tag string) (*SearchResult, error) {
 // DEPRECATED - Instagram does not allow Location search method.
 // Lat and Lng (Latitude & Longitude) cannot be ""
 func (search *Search) Location(lat, lng, location string) (*SearchResult, error) {
+	return search.LocationContext(context.Background(), lat, lng, location)
+}
+
+// LocationContext is the context-aware version of Location.
+func (search *Search) LocationContext(ctx context.Context, lat, lng, location string) (*SearchResult, error) {
 	insta := search.inst
 	q := map[string]string{
-		"rank_token":     insta.rankToken,
+		"rank_token":     insta.rankToken.Get(),
 		"latitude":       lat,
 		"longitude":      lng,
 		"ranked_content": "true",
@@ -141,7 +156,7 @@ func (search *Search) Location(lat, lng, location string) (*SearchResult, error)
 		q["timestamp"] = strconv.FormatInt(time.Now().Unix(), 10)
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSearchLocation,
 			Query:    q,
@@ -152,19 +167,24 @@ func (search *Search) Location(lat, lng, location string) (*SearchResult, error)
 	}
 
 	res := &SearchResult{}
-	err = json.Unmarshal(body, res)
+	err = insta.unmarshal(body, res)
 	return res, err
 }
 
 // Facebook search by facebook user.
 func (search *Search) Facebook(user string) (*SearchResult, error) {
+	return search.FacebookContext(context.Background(), user)
+}
+
+// FacebookContext is the context-aware version of Facebook.
+func (search *Search) FacebookContext(ctx context.Context, user string) (*SearchResult, error) {
 	insta := search.inst
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: urlSearchFacebook,
 			Query: map[string]string{
 				"query":      user,
-				"rank_token": insta.rankToken,
+				"rank_token": insta.rankToken.Get(),
 			},
 		},
 	)
@@ -172,6 +192,6 @@ func (search *Search) Facebook(user string) (*SearchResult, error) {
 		return nil, err
 	}
 	res := &SearchResult{}
-	err = json.Unmarshal(body, res)
+	err = insta.unmarshal(body, res)
 	return res, err
 }
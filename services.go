@@ -0,0 +1,133 @@
+package goinsta
+
+import (
+	"context"
+)
+
+// The interfaces below describe the sub-APIs of Instagram so code using
+// goinsta can depend on them and be tested with doubles, e.g.:
+//
+//	func notify(p goinsta.ProfilesService, name string) error {
+//		user, err := p.ByName(name)
+//		...
+//	}
+//
+// They are implemented by Profiles, Account, Search, Timeline, Inbox and Feed.
+
+// ProfilesService looks up users. It is implemented by *Profiles.
+type ProfilesService interface {
+	ByName(name string) (*User, error)
+	ByNameContext(ctx context.Context, name string) (*User, error)
+	ByID(id int64) (*User, error)
+	ByIDContext(ctx context.Context, id int64) (*User, error)
+	Blocked() ([]BlockedUser, error)
+	BlockedContext(ctx context.Context) ([]BlockedUser, error)
+}
+
+// AccountService manages the logged in account. It is implemented by *Account.
+type AccountService interface {
+	Sync() error
+	SyncContext(ctx context.Context) error
+	ChangePassword(old, new string) error
+	ChangePasswordContext(ctx context.Context, old, new string) error
+	RemoveProfilePic() error
+	RemoveProfilePicContext(ctx context.Context) error
+	SetPrivate() error
+	SetPrivateContext(ctx context.Context) error
+	SetPublic() error
+	SetPublicContext(ctx context.Context) error
+	SetBiography(bio string) error
+	SetBiographyContext(ctx context.Context, bio string) error
+	Followers() *Users
+	Following() *Users
+	Feed(params ...interface{}) *FeedMedia
+	Stories() *StoryMedia
+	Tags(minTimestamp []byte) (*FeedMedia, error)
+	TagsContext(ctx context.Context, minTimestamp []byte) (*FeedMedia, error)
+	Saved() (*SavedMedia, error)
+	SavedContext(ctx context.Context) (*SavedMedia, error)
+	Liked() *FeedMedia
+	PendingFollowRequests() ([]User, error)
+	PendingFollowRequestsContext(ctx context.Context) ([]User, error)
+	Archived(params ...interface{}) *FeedMedia
+}
+
+// SearchService searches users, tags and locations. It is implemented by *Search.
+type SearchService interface {
+	User(user string, countParam ...int) (*SearchResult, error)
+	UserContext(ctx context.Context, user string, countParam ...int) (*SearchResult, error)
+	Tags(tag string) (*SearchResult, error)
+	TagsContext(ctx context.Context, tag string) (*SearchResult, error)
+	Location(lat, lng, location string) (*SearchResult, error)
+	LocationContext(ctx context.Context, lat, lng, location string) (*SearchResult, error)
+	Facebook(user string) (*SearchResult, error)
+	FacebookContext(ctx context.Context, user string) (*SearchResult, error)
+}
+
+// TimelineService reads the home feed. It is implemented by *Timeline.
+type TimelineService interface {
+	Get() *FeedMedia
+	Stories() (*Tray, error)
+	StoriesContext(ctx context.Context) (*Tray, error)
+}
+
+// InboxService reads and sends direct messages. It is implemented by *Inbox.
+type InboxService interface {
+	Sync() error
+	SyncContext(ctx context.Context) error
+	SyncPending() error
+	SyncPendingContext(ctx context.Context) error
+	New(user *User, text string) error
+	NewContext(ctx context.Context, user *User, text string) error
+	Reset()
+	Next() bool
+	NextContext(ctx context.Context) bool
+	NextPending() bool
+	NextPendingContext(ctx context.Context) bool
+}
+
+// FeedService reads location and hashtag feeds. It is implemented by *Feed.
+type FeedService interface {
+	LocationID(locationID int64) (*FeedLocation, error)
+	LocationIDContext(ctx context.Context, locationID int64) (*FeedLocation, error)
+	Tags(tag string) (*FeedTag, error)
+	TagsContext(ctx context.Context, tag string) (*FeedTag, error)
+}
+
+var (
+	_ ProfilesService = (*Profiles)(nil)
+	_ AccountService  = (*Account)(nil)
+	_ SearchService   = (*Search)(nil)
+	_ TimelineService = (*Timeline)(nil)
+	_ InboxService    = (*Inbox)(nil)
+	_ FeedService     = (*Feed)(nil)
+)
+
+// Services are the sub-APIs of a session behind their interfaces.
+//
+// Code depending on Services instead of *Instagram can be given test
+// doubles by building the struct itself.
+type Services struct {
+	Profiles ProfilesService
+	// Account is nil until the session is logged in.
+	Account  AccountService
+	Search   SearchService
+	Timeline TimelineService
+	Inbox    InboxService
+	Feed     FeedService
+}
+
+// Services returns the sub-APIs of the session.
+func (inst *Instagram) Services() Services {
+	s := Services{
+		Profiles: inst.Profiles,
+		Search:   inst.Search,
+		Timeline: inst.Timeline,
+		Inbox:    inst.Inbox,
+		Feed:     inst.Feed,
+	}
+	if account := inst.account(); account != nil {
+		s.Account = account
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package goinsta
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeProfiles struct {
+	ProfilesService
+	users map[string]*User
+}
+
+func (p fakeProfiles) ByName(name string) (*User, error) {
+	if u, ok := p.users[name]; ok {
+		return u, nil
+	}
+	return nil, ErrNotFound
+}
+
+func TestServices(t *testing.T) {
+	insta := New("user", "pass")
+	s := insta.Services()
+	if s.Profiles != insta.Profiles || s.Inbox != insta.Inbox || s.Feed != insta.Feed {
+		t.Fatal("services are not the session sub-APIs")
+	}
+	if s.Account != nil {
+		t.Fatalf("got account %v before login", s.Account)
+	}
+
+	insta.Account = &Account{ID: 1, inst: insta}
+	if s = insta.Services(); s.Account != insta.Account {
+		t.Fatal("account service not set after login")
+	}
+
+	s.Profiles = fakeProfiles{users: map[string]*User{"bob": {ID: 2, Username: "bob"}}}
+	if u, err := s.Profiles.ByName("bob"); err != nil || u.ID != 2 {
+		t.Fatalf("got %v, %v", u, err)
+	}
+	if _, err := s.Profiles.ByName("carol"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,200 @@
+package goinsta
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrSessionNotFound is returned by SessionStore.Load when no session is
+// saved under the key.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionStore persists exported sessions by account key.
+//
+// Sessions are keyed by username and saved as written by Export.
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Load returns the session saved under key or ErrSessionNotFound.
+	Load(key string) ([]byte, error)
+	// Save stores the session under key, replacing any previous one.
+	Save(key string, session []byte) error
+	// Delete removes the session saved under key. Deleting a missing
+	// session is not an error.
+	Delete(key string) error
+}
+
+// SetSessionStore sets the store the session is saved to. nil disables it.
+//
+// The session is saved automatically after Login, after a challenge is
+// solved and whenever instagram updates the cookies of a logged in session.
+// It is deleted by Logout.
+func (inst *Instagram) SetSessionStore(store SessionStore) {
+	inst.mu.Lock()
+	inst.store = store
+	inst.mu.Unlock()
+}
+
+// WithSessionStore sets the store the session is saved to. See SetSessionStore.
+func WithSessionStore(store SessionStore) Option {
+	return func(inst *Instagram) error {
+		inst.SetSessionStore(store)
+		return nil
+	}
+}
+
+func (inst *Instagram) sessionStore() SessionStore {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.store
+}
+
+// saveSession saves the session to store.
+func (inst *Instagram) saveSession(store SessionStore) error {
+	var buf bytes.Buffer
+	if err := Export(inst, &buf); err != nil {
+		return err
+	}
+	return store.Save(inst.user, buf.Bytes())
+}
+
+// autoSave saves a logged in session, or one solving a challenge, to its
+// store, if any.
+func (inst *Instagram) autoSave(reason string) {
+	if inst.account() == nil && inst.challengeURL.Get() == "" {
+		return
+	}
+	inst.storeSession(reason)
+}
+
+// storeSession saves the session to its store, if any. Failures are
+// logged since they must not fail the API call.
+func (inst *Instagram) storeSession(reason string) {
+	store := inst.sessionStore()
+	if store == nil {
+		return
+	}
+	if err := inst.saveSession(store); err != nil {
+		inst.logger().Warn("cannot save session", "user", inst.user, "reason", reason, "error", err)
+		return
+	}
+	inst.logger().Debug("session saved", "user", inst.user, "reason", reason)
+}
+
+// LoadSession imports the session of username from store.
+// The imported session keeps saving to store.
+//
+// opts are applied as in ImportConfig.
+func LoadSession(store SessionStore, username string, opts ...Option) (*Instagram, error) {
+	session, err := store.Load(username)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]Option{WithSessionStore(store)}, opts...)
+	return ImportReader(bytes.NewReader(session), opts...)
+}
+
+// FileStore is a SessionStore keeping each session in a file of a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store saving sessions in dir.
+// The directory is created on the first save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+// Load implements SessionStore.
+func (s *FileStore) Load(key string) ([]byte, error) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrSessionNotFound
+	}
+	return b, err
+}
+
+// Save implements SessionStore. The session is written to a temporary file
+// renamed over the previous one, so readers never see a partial session.
+func (s *FileStore) Save(key string, session []byte) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	return writeSessionFile(s.path(key), session)
+}
+
+// writeSessionFile replaces the file path by one only readable by its
+// owner, whatever the mode of the replaced file. The file is written
+// under another name then renamed, so it is never partially written.
+func writeSessionFile(path string, session []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".session-")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(session)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Delete implements SessionStore.
+func (s *FileStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// MemoryStore is a SessionStore keeping sessions in memory.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string][]byte)}
+}
+
+// Load implements SessionStore.
+func (s *MemoryStore) Load(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	session, ok := s.sessions[key]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return append([]byte(nil), session...), nil
+}
+
+// Save implements SessionStore.
+func (s *MemoryStore) Save(key string, session []byte) error {
+	s.mu.Lock()
+	s.sessions[key] = append([]byte(nil), session...)
+	s.mu.Unlock()
+	return nil
+}
+
+// Delete implements SessionStore.
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.sessions, key)
+	s.mu.Unlock()
+	return nil
+}
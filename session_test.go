@@ -0,0 +1,213 @@
+package goinsta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionStore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: r.URL.Path, Path: "/"})
+		w.Write([]byte(`{"status":"ok","logged_in_user":{"pk":1,"username":"user"},"user":{"pk":1,"username":"user"}}`))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithSessionStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("user"); err != nil {
+		t.Fatalf("session not saved after login: %v", err)
+	}
+
+	// refreshed cookies are saved
+	store.Delete("user")
+	if _, err := insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("user"); err != nil {
+		t.Fatalf("session not saved after a cookie update: %v", err)
+	}
+
+	loaded, err := LoadSession(store, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Account.ID != 1 || loaded.sessionStore() != store {
+		t.Fatalf("loaded session differs: account %d", loaded.Account.ID)
+	}
+
+	if err := insta.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("user"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("got %v after logout, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionKeptOnLogoutFailure(t *testing.T) {
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"status":"fail","message":"try again later"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok","logged_in_user":{"pk":1,"username":"user"}}`))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithSessionStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+
+	fail = true
+	if err := insta.Logout(); err == nil {
+		t.Fatal("logout succeeded on an error response")
+	}
+	if _, err := store.Load("user"); err != nil {
+		t.Fatalf("session deleted after a failed logout: %v", err)
+	}
+
+	fail = false
+	if err := insta.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("user"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("got %v after logout, want ErrSessionNotFound", err)
+	}
+}
+
+// countingStore counts the saves of a MemoryStore.
+type countingStore struct {
+	*MemoryStore
+	saves int
+}
+
+func (s *countingStore) Save(key string, session []byte) error {
+	s.saves++
+	return s.MemoryStore.Save(key, session)
+}
+
+func TestSessionSavedOnCookieChange(t *testing.T) {
+	sessionID := "first"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: sessionID, Path: "/"})
+		w.Write([]byte(`{"status":"ok","logged_in_user":{"pk":1,"username":"user"}}`))
+	}))
+	defer srv.Close()
+
+	store := &countingStore{MemoryStore: NewMemoryStore()}
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithSessionStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insta.Login(); err != nil {
+		t.Fatal(err)
+	}
+
+	saves := store.saves
+	for i := 0; i < 3; i++ {
+		if _, err := insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.saves != saves {
+		t.Fatalf("session saved %d times for unchanged cookies", store.saves-saves)
+	}
+
+	sessionID = "second"
+	if _, err := insta.sendRequest(context.Background(), &reqOptions{Endpoint: urlTimeline}); err != nil {
+		t.Fatal(err)
+	}
+	if store.saves != saves+1 {
+		t.Fatalf("got %d saves after a cookie change, want 1", store.saves-saves)
+	}
+}
+
+func TestSessionSavedOnChallengeClose(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok","action":"close"}`))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	insta, err := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithSessionStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	insta.Challenge.start("/challenge/1/abc/")
+	loaded, err := LoadSession(store, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.challengeURL.Get() != "challenge/1/abc/" {
+		t.Fatalf("challenge URL %q not saved", loaded.challengeURL.Get())
+	}
+
+	// the challenge is closed without logging in
+	if err := insta.Challenge.Solve(nil); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = LoadSession(store, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.challengeURL.Get() != "" {
+		t.Fatalf("closed challenge %q still saved", loaded.challengeURL.Get())
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	store := NewFileStore(dir)
+	if _, err := store.Load("a/b"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("got %v, want ErrSessionNotFound", err)
+	}
+	if err := store.Save("a/b", []byte("session")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("a/b", []byte("refreshed")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := store.Load("a/b")
+	if err != nil || string(b) != "refreshed" {
+		t.Fatalf("got %q, %v", b, err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("got files %v, %v", files, err)
+	}
+	fi, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("got mode %v, want 0600", fi.Mode())
+	}
+
+	if err := store.Delete("a/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("a/b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("a/b"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("got %v after delete, want ErrSessionNotFound", err)
+	}
+}
@@ -3,7 +3,7 @@ package tests
 import "testing"
 
 func TestFeedTagLike(t *testing.T) {
-	insta, err := getRandomAccount()
+	insta, err := getAccount(t)
 	if err != nil {
 		t.Fatal(err)
 		return
@@ -31,7 +31,7 @@ func TestFeedTagLike(t *testing.T) {
 }
 
 func TestFeedTagNext(t *testing.T) {
-	insta, err := getRandomAccount()
+	insta, err := getAccount(t)
 	if err != nil {
 		t.Fatal(err)
 		return
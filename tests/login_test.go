@@ -5,7 +5,7 @@ import (
 )
 
 func TestImportAccount(t *testing.T) {
-	insta, err := getRandomAccount()
+	insta, err := getAccount(t)
 	if err != nil {
 		t.Fatal(err)
 		return
@@ -6,7 +6,7 @@ import (
 
 func TestSearchUser(t *testing.T) {
 	count := 20
-	insta, err := getRandomAccount()
+	insta, err := getAccount(t)
 	if err != nil {
 		t.Fatal(err)
 		return
@@ -6,17 +6,19 @@ import (
 	"errors"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
+	"testing"
 
-	"github.com/ahmdrz/goinsta"
+	"github.com/ahmdrz/goinsta/v2"
 )
 
-func readFromBase64(base64EncodedString string) (*goinsta.Instagram, error) {
+func readFromBase64(base64EncodedString string, opts ...goinsta.Option) (*goinsta.Instagram, error) {
 	base64Bytes, err := base64.StdEncoding.DecodeString(base64EncodedString)
 	if err != nil {
 		return nil, err
 	}
-	return goinsta.ImportReader(bytes.NewReader(base64Bytes))
+	return goinsta.ImportReader(bytes.NewReader(base64Bytes), opts...)
 }
 
 func availableEncodedAccounts() []string {
@@ -34,12 +36,50 @@ func availableEncodedAccounts() []string {
 	return output
 }
 
-func getRandomAccount() (*goinsta.Instagram, error) {
+func getRandomAccount(opts ...goinsta.Option) (*goinsta.Instagram, error) {
 	accounts := availableEncodedAccounts()
 	if len(accounts) == 0 {
 		return nil, errors.New("there is no encoded account in environ")
 	}
 
 	encodedAccount := accounts[rand.Intn(len(accounts))]
-	return readFromBase64(encodedAccount)
+	return readFromBase64(encodedAccount, opts...)
+}
+
+// getAccount returns a session for test t.
+//
+// Without INSTAGRAM_BASE64_* accounts in environ, the session replays
+// testdata/<test name>.jsonl offline. Otherwise a live account is used, and
+// its requests are recorded to that file when GOINSTA_RECORD is set.
+//
+// The cassettes in testdata are written by hand in the recorded format,
+// with made up users and media: they were not recorded from a live account.
+func getAccount(t *testing.T) (*goinsta.Instagram, error) {
+	cassette := filepath.Join("testdata", t.Name()+".jsonl")
+
+	if len(availableEncodedAccounts()) != 0 {
+		if os.Getenv("GOINSTA_RECORD") == "" {
+			return getRandomAccount()
+		}
+		f, err := os.Create(cassette)
+		if err != nil {
+			return nil, err
+		}
+		t.Cleanup(func() { f.Close() })
+		return getRandomAccount(goinsta.WithTransport(goinsta.NewRecorder(f, nil)))
+	}
+
+	replayer, err := goinsta.LoadCassette(cassette)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() {
+		if pending := replayer.Pending(); len(pending) != 0 {
+			t.Errorf("interactions not replayed: %v", pending)
+		}
+	})
+	return goinsta.ImportConfig(
+		goinsta.ConfigFile{ID: 1234567890, User: "goinsta"},
+		goinsta.WithTransport(replayer),
+	)
 }
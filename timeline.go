@@ -1,7 +1,7 @@
 package goinsta
 
 import (
-	"encoding/json"
+	"context"
 )
 
 // Timeline is the object to represent the main feed on instagram, the first page that shows the latest feeds of my following contacts.
@@ -29,10 +29,15 @@ func (time *Timeline) Get() *FeedMedia {
 
 // Stories returns slice of StoryMedia
 func (time *Timeline) Stories() (*Tray, error) {
-	body, err := time.inst.sendSimpleRequest(urlStories)
+	return time.StoriesContext(context.Background())
+}
+
+// StoriesContext is the context-aware version of Stories.
+func (time *Timeline) StoriesContext(ctx context.Context) (*Tray, error) {
+	body, err := time.inst.sendSimpleRequest(ctx, urlStories)
 	if err == nil {
 		tray := &Tray{}
-		err = json.Unmarshal(body, tray)
+		err = time.inst.unmarshal(body, tray)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,220 @@
+package goinsta
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TwoFactorMethod is a way to receive the verification code of a two
+// factor login.
+type TwoFactorMethod string
+
+// Two factor verification methods.
+const (
+	// TwoFactorSMS is the code sent by SMS.
+	TwoFactorSMS TwoFactorMethod = "1"
+	// TwoFactorTOTP is the code of an authenticator app. See TOTPCode.
+	TwoFactorTOTP TwoFactorMethod = "3"
+)
+
+// TwoFactorInfo describes a login waiting for its two factor verification code.
+//
+// It is returned by Login in a TwoFactorError.
+type TwoFactorInfo struct {
+	Username              string `json:"username"`
+	ID                    int64  `json:"pk"`
+	TwoFactorIdentifier   string `json:"two_factor_identifier"`
+	ObfuscatedPhoneNumber string `json:"obfuscated_phone_number"`
+	SMSTwoFactorOn        bool   `json:"sms_two_factor_on"`
+	TOTPTwoFactorOn       bool   `json:"totp_two_factor_on"`
+	ShowMessengerCode     bool   `json:"show_messenger_code_option"`
+	ShowNewLoginScreen    bool   `json:"show_new_login_screen"`
+	ShowTrustedDevice     bool   `json:"show_trusted_device_option"`
+}
+
+// Methods returns the verification methods enabled on the account,
+// SMS first.
+func (info TwoFactorInfo) Methods() []TwoFactorMethod {
+	var methods []TwoFactorMethod
+	if info.SMSTwoFactorOn {
+		methods = append(methods, TwoFactorSMS)
+	}
+	if info.TOTPTwoFactorOn {
+		methods = append(methods, TwoFactorTOTP)
+	}
+	return methods
+}
+
+// ErrNoTwoFactor is returned by TwoFactorLogin and ResendTwoFactorSMS when
+// no login is waiting for its verification code.
+var ErrNoTwoFactor = errors.New("no two factor login in progress")
+
+// pendingTwoFactor returns the login waiting for its verification code.
+func (inst *Instagram) pendingTwoFactor() (TwoFactorInfo, error) {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.twoFactor == nil {
+		return TwoFactorInfo{}, ErrNoTwoFactor
+	}
+	return *inst.twoFactor, nil
+}
+
+// TwoFactorLogin finishes a login that failed with ErrTwoFactorRequired
+// by sending the verification code.
+//
+// method is the way the code was received. By default it is the first
+// method returned by TwoFactorInfo.Methods.
+func (inst *Instagram) TwoFactorLogin(code string, method ...TwoFactorMethod) error {
+	return inst.TwoFactorLoginContext(context.Background(), code, method...)
+}
+
+// TwoFactorLoginContext is the context-aware version of TwoFactorLogin.
+func (inst *Instagram) TwoFactorLoginContext(ctx context.Context, code string, method ...TwoFactorMethod) error {
+	info, err := inst.pendingTwoFactor()
+	if err != nil {
+		return err
+	}
+	verification := TwoFactorSMS
+	if len(method) > 0 {
+		verification = method[0]
+	} else if methods := info.Methods(); len(methods) > 0 {
+		verification = methods[0]
+	}
+
+	data, err := inst.prepareData(
+		map[string]interface{}{
+			"verification_code":     strings.Replace(code, " ", "", -1),
+			"two_factor_identifier": info.TwoFactorIdentifier,
+			"verification_method":   string(verification),
+			"trust_this_device":     "1",
+			"username":              inst.user,
+			"device_id":             inst.dID.Get(),
+			"guid":                  inst.uuid.Get(),
+			"phone_id":              inst.pid.Get(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	body, err := inst.sendRequest(ctx,
+		&reqOptions{
+			Endpoint:  urlTwoFactorLogin,
+			Query:     inst.generateSignature(data),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	inst.pass.Set("")
+	inst.logger().Info("logged in by two factor authentication", "user", inst.user)
+
+	res := accountResp{}
+	err = inst.unmarshal(body, &res)
+	if err != nil {
+		return err
+	}
+
+	inst.setAccount(&res.Account)
+	inst.zrToken(ctx)
+	inst.autoSave("two_factor")
+
+	return err
+}
+
+// TwoFactorLoginTOTP finishes a login that failed with ErrTwoFactorRequired
+// with the code computed from secret, the key of the authenticator app.
+//
+// See TOTPCode.
+func (inst *Instagram) TwoFactorLoginTOTP(secret string) error {
+	return inst.TwoFactorLoginTOTPContext(context.Background(), secret)
+}
+
+// TwoFactorLoginTOTPContext is the context-aware version of TwoFactorLoginTOTP.
+func (inst *Instagram) TwoFactorLoginTOTPContext(ctx context.Context, secret string) error {
+	code, err := TOTPCode(secret, time.Now())
+	if err != nil {
+		return err
+	}
+	return inst.TwoFactorLoginContext(ctx, code, TwoFactorTOTP)
+}
+
+// ResendTwoFactorSMS sends the verification code of the current two
+// factor login by SMS again.
+func (inst *Instagram) ResendTwoFactorSMS() error {
+	return inst.ResendTwoFactorSMSContext(context.Background())
+}
+
+// ResendTwoFactorSMSContext is the context-aware version of ResendTwoFactorSMS.
+func (inst *Instagram) ResendTwoFactorSMSContext(ctx context.Context) error {
+	info, err := inst.pendingTwoFactor()
+	if err != nil {
+		return err
+	}
+	data, err := inst.prepareData(
+		map[string]interface{}{
+			"two_factor_identifier": info.TwoFactorIdentifier,
+			"username":              inst.user,
+			"device_id":             inst.dID.Get(),
+			"guid":                  inst.uuid.Get(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	body, err := inst.sendRequest(ctx,
+		&reqOptions{
+			Endpoint:  urlTwoFactorSMS,
+			Query:     inst.generateSignature(data),
+			IsPost:    true,
+			Login:     true,
+			Handshake: true,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	res := struct {
+		Info *TwoFactorInfo `json:"two_factor_info"`
+	}{}
+	err = inst.unmarshal(body, &res)
+	if err == nil && res.Info != nil {
+		inst.mu.Lock()
+		inst.twoFactor = res.Info
+		inst.mu.Unlock()
+	}
+	return err
+}
+
+// TOTPCode returns the time-based one-time password (RFC 6238) of secret
+// at t, as shown by authenticator apps.
+//
+// secret is the base32 key given by instagram when the authenticator app
+// was set up. Spaces and case are ignored.
+func TOTPCode(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %v", err)
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000), nil
+}
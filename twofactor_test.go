@@ -0,0 +1,36 @@
+package goinsta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 test vectors for SHA1, truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	for _, test := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{20000000000, "353130"},
+	} {
+		code, err := TOTPCode(secret, time.Unix(test.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code {
+			t.Fatalf("got %s at %d, want %s", code, test.unix, test.code)
+		}
+	}
+
+	// authenticator apps show secrets in lowercase groups
+	if code, err := TOTPCode("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0)); err != nil || code != "287082" {
+		t.Fatalf("got %s, %v", code, err)
+	}
+	if _, err := TOTPCode("not base32!", time.Now()); err == nil {
+		t.Fatal("invalid secret accepted")
+	}
+}
@@ -1,13 +1,18 @@
 package goinsta
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ConfigFile is a structure to store the session information so that can be exported or imported.
 type ConfigFile struct {
+	// Version is the version of the config format, 0 for configs exported
+	// before it was stored. See ConfigVersion.
+	Version   int            `json:"version"`
 	ID        int64          `json:"id"`
 	User      string         `json:"username"`
 	DeviceID  string         `json:"device_id"`
@@ -16,6 +21,20 @@ type ConfigFile struct {
 	Token     string         `json:"token"`
 	PhoneID   string         `json:"phone_id"`
 	Cookies   []*http.Cookie `json:"cookies"`
+	Hosts     Hosts          `json:"hosts"`
+	// Device and App are nil in sessions exported before they were stored.
+	Device *Device     `json:"device,omitempty"`
+	App    *AppProfile `json:"app,omitempty"`
+	AdID   string      `json:"adid,omitempty"`
+	Locale string      `json:"locale,omitempty"`
+	// ChallengeURL is the challenge being solved, if any.
+	ChallengeURL string `json:"challenge_url,omitempty"`
+	// Proxy is nil if the session does not use a proxy set by SetProxy.
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+	// CreatedAt is the time the session logged in.
+	CreatedAt time.Time `json:"created_at"`
+	// RefreshedAt is the last time instagram updated the session cookies.
+	RefreshedAt time.Time `json:"refreshed_at"`
 }
 
 // School is void structure (yet).
@@ -84,6 +103,19 @@ func (e ChallengeError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Status, e.Message)
 }
 
+// TwoFactorError is error returned by Login when the account is protected
+// by two factor authentication.
+type TwoFactorError struct {
+	Message   string        `json:"message"`
+	Info      TwoFactorInfo `json:"two_factor_info"`
+	Status    string        `json:"status"`
+	ErrorType string        `json:"error_type"`
+}
+
+func (e TwoFactorError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Message)
+}
+
 // Nametag is part of the account information.
 type Nametag struct {
 	Mode          int64       `json:"mode"`
@@ -315,8 +347,13 @@ type BlockedUser struct {
 
 // Unblock unblocks blocked user.
 func (b *BlockedUser) Unblock() error {
+	return b.UnblockContext(context.Background())
+}
+
+// UnblockContext is the context-aware version of Unblock.
+func (b *BlockedUser) UnblockContext(ctx context.Context) error {
 	u := User{ID: b.UserID}
-	return u.Unblock()
+	return u.UnblockContext(ctx)
 }
 
 type blockedListResp struct {
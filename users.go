@@ -1,6 +1,7 @@
 package goinsta
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -47,6 +48,11 @@ var ErrNoMore = errors.New("List end have been reached")
 //
 // returns false when list reach the end.
 func (users *Users) Next() bool {
+	return users.NextContext(context.Background())
+}
+
+// NextContext is the context-aware version of Next.
+func (users *Users) NextContext(ctx context.Context) bool {
 	if users.err != nil {
 		return false
 	}
@@ -54,19 +60,19 @@ func (users *Users) Next() bool {
 	insta := users.inst
 	endpoint := users.endpoint
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: endpoint,
 			Query: map[string]string{
 				"max_id":             users.NextID,
-				"ig_sig_key_version": goInstaSigKeyVersion,
-				"rank_token":         insta.rankToken,
+				"ig_sig_key_version": insta.app.SigKeyVersion,
+				"rank_token":         insta.rankToken.Get(),
 			},
 		},
 	)
 	if err == nil {
 		usrs := Users{}
-		err = json.Unmarshal(body, &usrs)
+		err = insta.unmarshal(body, &usrs)
 		if err == nil {
 			if len(usrs.RawNextID) > 0 && usrs.RawNextID[0] == '"' && usrs.RawNextID[len(usrs.RawNextID)-1] == '"' {
 				if err := json.Unmarshal(usrs.RawNextID, &usrs.NextID); err != nil {
@@ -198,11 +204,16 @@ func (inst *Instagram) NewUser() *User {
 //
 // See example: examples/user/friendship.go
 func (user *User) Sync(params ...interface{}) error {
+	return user.SyncContext(context.Background(), params...)
+}
+
+// SyncContext is the context-aware version of Sync.
+func (user *User) SyncContext(ctx context.Context, params ...interface{}) error {
 	insta := user.inst
-	body, err := insta.sendSimpleRequest(urlUserInfo, user.ID)
+	body, err := insta.sendSimpleRequest(ctx, urlUserInfo, user.ID)
 	if err == nil {
 		resp := userResp{}
-		err = json.Unmarshal(body, &resp)
+		err = insta.unmarshal(body, &resp)
 		if err == nil {
 			*user = resp.User
 			user.inst = insta
@@ -210,7 +221,7 @@ func (user *User) Sync(params ...interface{}) error {
 				switch b := param.(type) {
 				case bool:
 					if b {
-						err = user.FriendShip()
+						err = user.FriendShipContext(ctx)
 					}
 				}
 			}
@@ -249,6 +260,11 @@ func (user *User) Followers() *Users {
 //
 // See example: examples/user/block.go
 func (user *User) Block() error {
+	return user.BlockContext(context.Background())
+}
+
+// BlockContext is the context-aware version of Block.
+func (user *User) BlockContext(ctx context.Context) error {
 	insta := user.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -258,10 +274,10 @@ func (user *User) Block() error {
 	if err != nil {
 		return err
 	}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserBlock, user.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -269,7 +285,7 @@ func (user *User) Block() error {
 		return err
 	}
 	resp := friendResp{}
-	err = json.Unmarshal(body, &resp)
+	err = insta.unmarshal(body, &resp)
 	user.Friendship = resp.Friendship
 	if err != nil {
 		return err
@@ -284,6 +300,11 @@ func (user *User) Block() error {
 //
 // See example: examples/user/unblock.go
 func (user *User) Unblock() error {
+	return user.UnblockContext(context.Background())
+}
+
+// UnblockContext is the context-aware version of Unblock.
+func (user *User) UnblockContext(ctx context.Context) error {
 	insta := user.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -293,10 +314,10 @@ func (user *User) Unblock() error {
 	if err != nil {
 		return err
 	}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserUnblock, user.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -304,7 +325,7 @@ func (user *User) Unblock() error {
 		return err
 	}
 	resp := friendResp{}
-	err = json.Unmarshal(body, &resp)
+	err = insta.unmarshal(body, &resp)
 	user.Friendship = resp.Friendship
 	if err != nil {
 		return err
@@ -319,7 +340,12 @@ func (user *User) Unblock() error {
 // goinsta.MuteAll, goinsta.MuteStory, goinsta.MuteFeed
 // This function updates current User.Friendship structure.
 func (user *User) Mute(opt muteOption) error {
-	return muteOrUnmute(user, opt, urlUserMute)
+	return user.MuteContext(context.Background(), opt)
+}
+
+// MuteContext is the context-aware version of Mute.
+func (user *User) MuteContext(ctx context.Context, opt muteOption) error {
+	return muteOrUnmute(ctx, user, opt, urlUserMute)
 }
 
 // Unmute unmutes user so it appears in the feed or story reel again
@@ -328,10 +354,15 @@ func (user *User) Mute(opt muteOption) error {
 // goinsta.MuteAll, goinsta.MuteStory, goinsta.MuteFeed
 // This function updates current User.Friendship structure.
 func (user *User) Unmute(opt muteOption) error {
-	return muteOrUnmute(user, opt, urlUserUnmute)
+	return user.UnmuteContext(context.Background(), opt)
+}
+
+// UnmuteContext is the context-aware version of Unmute.
+func (user *User) UnmuteContext(ctx context.Context, opt muteOption) error {
+	return muteOrUnmute(ctx, user, opt, urlUserUnmute)
 }
 
-func muteOrUnmute(user *User, opt muteOption, endpoint string) error {
+func muteOrUnmute(ctx context.Context, user *User, opt muteOption, endpoint string) error {
 	insta := user.inst
 	data, err := insta.prepareData(
 		generateMuteData(user, opt),
@@ -339,10 +370,10 @@ func muteOrUnmute(user *User, opt muteOption, endpoint string) error {
 	if err != nil {
 		return err
 	}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: endpoint,
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -350,7 +381,7 @@ func muteOrUnmute(user *User, opt muteOption, endpoint string) error {
 		return err
 	}
 	resp := friendResp{}
-	err = json.Unmarshal(body, &resp)
+	err = insta.unmarshal(body, &resp)
 	user.Friendship = resp.Friendship
 	if err != nil {
 		return err
@@ -386,6 +417,11 @@ func generateMuteData(user *User, opt muteOption) map[string]interface{} {
 //
 // See example: examples/user/follow.go
 func (user *User) Follow() error {
+	return user.FollowContext(context.Background())
+}
+
+// FollowContext is the context-aware version of Follow.
+func (user *User) FollowContext(ctx context.Context) error {
 	insta := user.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -395,10 +431,10 @@ func (user *User) Follow() error {
 	if err != nil {
 		return err
 	}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserFollow, user.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -406,7 +442,7 @@ func (user *User) Follow() error {
 		return err
 	}
 	resp := friendResp{}
-	err = json.Unmarshal(body, &resp)
+	err = insta.unmarshal(body, &resp)
 	user.Friendship = resp.Friendship
 	if err != nil {
 		return err
@@ -421,6 +457,11 @@ func (user *User) Follow() error {
 //
 // See example: examples/user/unfollow.go
 func (user *User) Unfollow() error {
+	return user.UnfollowContext(context.Background())
+}
+
+// UnfollowContext is the context-aware version of Unfollow.
+func (user *User) UnfollowContext(ctx context.Context) error {
 	insta := user.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -430,10 +471,10 @@ func (user *User) Unfollow() error {
 	if err != nil {
 		return err
 	}
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserUnfollow, user.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 			IsPost:   true,
 		},
 	)
@@ -441,7 +482,7 @@ func (user *User) Unfollow() error {
 		return err
 	}
 	resp := friendResp{}
-	err = json.Unmarshal(body, &resp)
+	err = insta.unmarshal(body, &resp)
 	user.Friendship = resp.Friendship
 	if err != nil {
 		return err
@@ -454,6 +495,11 @@ func (user *User) Unfollow() error {
 //
 // The result is stored in user.Friendship
 func (user *User) FriendShip() error {
+	return user.FriendShipContext(context.Background())
+}
+
+// FriendShipContext is the context-aware version of FriendShip.
+func (user *User) FriendShipContext(ctx context.Context) error {
 	insta := user.inst
 	data, err := insta.prepareData(
 		map[string]interface{}{
@@ -464,14 +510,14 @@ func (user *User) FriendShip() error {
 		return err
 	}
 
-	body, err := insta.sendRequest(
+	body, err := insta.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlFriendship, user.ID),
-			Query:    generateSignature(data),
+			Query:    insta.generateSignature(data),
 		},
 	)
 	if err == nil {
-		err = json.Unmarshal(body, &user.Friendship)
+		err = insta.unmarshal(body, &user.Friendship)
 	}
 	return err
 }
@@ -519,6 +565,11 @@ func (user *User) Stories() *StoryMedia {
 //
 // See example: examples/user/highlights.go
 func (user *User) Highlights() ([]StoryMedia, error) {
+	return user.HighlightsContext(context.Background())
+}
+
+// HighlightsContext is the context-aware version of Highlights.
+func (user *User) HighlightsContext(ctx context.Context) ([]StoryMedia, error) {
 	query := []trayRequest{
 		{"SUPPORTED_SDK_VERSIONS", "9.0,10.0,11.0,12.0,13.0,14.0,15.0,16.0,17.0,18.0,19.0,20.0,21.0,22.0,23.0,24.0"},
 		{"FACE_TRACKER_VERSION", "10"},
@@ -529,20 +580,20 @@ func (user *User) Highlights() ([]StoryMedia, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := user.inst.sendRequest(
+	body, err := user.inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserHighlights, user.ID),
-			Query:    generateSignature(b2s(data)),
+			Query:    user.inst.generateSignature(b2s(data)),
 		},
 	)
 	if err == nil {
 		tray := &Tray{}
-		err = json.Unmarshal(body, &tray)
+		err = user.inst.unmarshal(body, &tray)
 		if err == nil {
 			tray.set(user.inst, "")
 			for i := range tray.Stories {
 				if len(tray.Stories[i].Items) == 0 {
-					err = tray.Stories[i].Sync()
+					err = tray.Stories[i].SyncContext(ctx)
 					if err != nil {
 						return nil, err
 					}
@@ -560,13 +611,18 @@ func (user *User) Highlights() ([]StoryMedia, error) {
 //
 // See example: examples/user/tags.go
 func (user *User) Tags(minTimestamp []byte) (*FeedMedia, error) {
+	return user.TagsContext(context.Background(), minTimestamp)
+}
+
+// TagsContext is the context-aware version of Tags.
+func (user *User) TagsContext(ctx context.Context, minTimestamp []byte) (*FeedMedia, error) {
 	timestamp := b2s(minTimestamp)
-	body, err := user.inst.sendRequest(
+	body, err := user.inst.sendRequest(ctx,
 		&reqOptions{
 			Endpoint: fmt.Sprintf(urlUserTags, user.ID),
 			Query: map[string]string{
 				"max_id":         "",
-				"rank_token":     user.inst.rankToken,
+				"rank_token":     user.inst.rankToken.Get(),
 				"min_timestamp":  timestamp,
 				"ranked_content": "true",
 			},
@@ -577,7 +633,7 @@ func (user *User) Tags(minTimestamp []byte) (*FeedMedia, error) {
 	}
 
 	media := &FeedMedia{}
-	err = json.Unmarshal(body, media)
+	err = user.inst.unmarshal(body, media)
 	media.inst = user.inst
 	media.endpoint = urlUserTags
 	media.uid = user.ID
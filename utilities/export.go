@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 
-	"github.com/ahmdrz/goinsta"
+	"github.com/ahmdrz/goinsta/v2"
 )
 
 // ExportAsBytes exports selected *Instagram object as []byte
@@ -27,3 +27,14 @@ func ExportAsBase64String(insta *goinsta.Instagram) (string, error) {
 	sEnc := base64.StdEncoding.EncodeToString(bytes)
 	return sEnc, nil
 }
+
+// ExportAsEncryptedBase64String exports selected *Instagram object encrypted
+// with passphrase as base64 encoded string. See goinsta.ExportEncrypted.
+func ExportAsEncryptedBase64String(insta *goinsta.Instagram, passphrase string) (string, error) {
+	buffer := &bytes.Buffer{}
+	err := goinsta.ExportEncrypted(insta, buffer, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
@@ -4,19 +4,19 @@ import (
 	"bytes"
 	"encoding/base64"
 
-	"github.com/ahmdrz/goinsta"
+	"github.com/ahmdrz/goinsta/v2"
 )
 
 // ImportFromBytes imports instagram configuration from an array of bytes.
 //
-// This function does not set proxy automatically. Use SetProxy after this call.
+// The proxy of the exported session is restored, if any.
 func ImportFromBytes(inputBytes []byte) (*goinsta.Instagram, error) {
 	return goinsta.ImportReader(bytes.NewReader(inputBytes))
 }
 
 // ImportFromBase64String imports instagram configuration from a base64 encoded string.
 //
-// This function does not set proxy automatically. Use SetProxy after this call.
+// The proxy of the exported session is restored, if any.
 func ImportFromBase64String(base64String string) (*goinsta.Instagram, error) {
 	sDec, err := base64.StdEncoding.DecodeString(base64String)
 	if err != nil {
@@ -25,3 +25,16 @@ func ImportFromBase64String(base64String string) (*goinsta.Instagram, error) {
 
 	return ImportFromBytes(sDec)
 }
+
+// ImportFromEncryptedBase64String imports instagram configuration from a base64
+// encoded string made by ExportAsEncryptedBase64String.
+//
+// The proxy of the exported session is restored, if any.
+func ImportFromEncryptedBase64String(base64String, passphrase string) (*goinsta.Instagram, error) {
+	sDec, err := base64.StdEncoding.DecodeString(base64String)
+	if err != nil {
+		return nil, err
+	}
+
+	return goinsta.ImportEncrypted(bytes.NewReader(sDec), passphrase)
+}
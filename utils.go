@@ -1,6 +1,7 @@
 package goinsta
 
 import (
+	"context"
 	"encoding/json"
 	"image"
 	// Required for getImageDimensionFromReader in jpg and png format
@@ -8,6 +9,7 @@ import (
 	_ "image/png"
 	"io"
 	"strconv"
+	"sync"
 	"unsafe"
 )
 
@@ -71,6 +73,37 @@ func prepareRecipients(cc interface{}) (bb string, err error) {
 	return
 }
 
+// contextReader stops reading as soon as ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// syncString is a string safe for concurrent use.
+type syncString struct {
+	mu sync.RWMutex
+	s  string
+}
+
+func (s *syncString) Get() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s
+}
+
+func (s *syncString) Set(v string) {
+	s.mu.Lock()
+	s.s = v
+	s.mu.Unlock()
+}
+
 // getImageDimensionFromReader return image dimension , types is .jpg and .png
 func getImageDimensionFromReader(rdr io.Reader) (int, int, error) {
 	image, _, err := image.DecodeConfig(rdr)